@@ -2,10 +2,19 @@ package main
 
 import (
 	"absence/internal"
+	"absence/internal/middleware"
+	"absence/internal/model"
+	"absence/internal/service"
 	"absence/pkg/database"
 	"absence/pkg/jwt"
+	"absence/pkg/notifier"
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "absence/docs" // This will be generated by swag
@@ -29,7 +38,7 @@ import (
 // @license.url   http://www.apache.org/licenses/LICENSE-2.0.html
 
 // @host      localhost:8080
-// @BasePath  /api/v1
+// @BasePath  /api
 
 // @securityDefinitions.apikey BearerAuth
 // @in header
@@ -60,48 +69,345 @@ func main() {
 	}
 
 	// Initialize JWT manager
-	secretKey := os.Getenv("JWT_SECRET_KEY")
-	if secretKey == "" {
-		secretKey = "your-secret-key" // Default secret key for development
+	signingKeys, err := loadSigningKeys()
+	if err != nil {
+		log.Fatal("Failed to load JWT signing keys:", err)
+	}
+	tokenDuration, err := hoursFromEnv("JWT_EXPIRATION_HOURS", 24)
+	if err != nil {
+		log.Fatal("Invalid JWT_EXPIRATION_HOURS:", err)
+	}
+	refreshDuration, err := hoursFromEnv("JWT_REFRESH_EXPIRATION_HOURS", 24*30)
+	if err != nil {
+		log.Fatal("Invalid JWT_REFRESH_EXPIRATION_HOURS:", err)
+	}
+	jwtManager := jwt.NewJWTManager(signingKeys, tokenDuration, refreshDuration)
+
+	// Attendance rules
+	holidayPolicy, err := service.ParseHolidayPolicy(os.Getenv("HOLIDAY_POLICY"))
+	if err != nil {
+		log.Fatal("Invalid HOLIDAY_POLICY:", err)
+	}
+	geofencePolicy, err := service.ParseGeofencePolicy(os.Getenv("GEOFENCE_POLICY"))
+	if err != nil {
+		log.Fatal("Invalid GEOFENCE_POLICY:", err)
+	}
+	absenceCutoff := os.Getenv("ABSENCE_CUTOFF")
+	if absenceCutoff == "" {
+		absenceCutoff = "23:00" // Mark missing check-ins late in the evening by default
+	}
+	cutoff, err := time.Parse("15:04", absenceCutoff)
+	if err != nil {
+		log.Fatal("Invalid ABSENCE_CUTOFF, expected HH:MM:", err)
+	}
+	maxSession, err := hoursFromEnv("ATTENDANCE_MAX_SESSION_HOURS", 24)
+	if err != nil || maxSession <= 0 {
+		log.Fatal("Invalid ATTENDANCE_MAX_SESSION_HOURS: must be a positive number of hours")
+	}
+	maxBreak, err := strconv.Atoi(envOrDefault("BREAK_MAX_MINUTES", "0"))
+	if err != nil || maxBreak < 0 {
+		log.Fatal("Invalid BREAK_MAX_MINUTES: must be a number of minutes, 0 to disable")
+	}
+	breakRequiredAfter, err := strconv.Atoi(envOrDefault("BREAK_REQUIRED_AFTER_MINUTES", "0"))
+	if err != nil || breakRequiredAfter < 0 {
+		log.Fatal("Invalid BREAK_REQUIRED_AFTER_MINUTES: must be a number of minutes, 0 to disable")
+	}
+	breakRequired, err := strconv.Atoi(envOrDefault("BREAK_REQUIRED_MINUTES", "30"))
+	if err != nil || breakRequired < 0 {
+		log.Fatal("Invalid BREAK_REQUIRED_MINUTES: must be a number of minutes")
+	}
+	dailyThreshold, err := hoursFromEnv("OVERTIME_DAILY_HOURS", 8)
+	if err != nil || dailyThreshold < 0 {
+		log.Fatal("Invalid OVERTIME_DAILY_HOURS: must be a number of hours, 0 to disable")
+	}
+	weeklyThreshold, err := hoursFromEnv("OVERTIME_WEEKLY_HOURS", 40)
+	if err != nil || weeklyThreshold < 0 {
+		log.Fatal("Invalid OVERTIME_WEEKLY_HOURS: must be a number of hours, 0 to disable")
+	}
+	weekendMultiplier, err := strconv.ParseFloat(envOrDefault("OVERTIME_WEEKEND_MULTIPLIER", "1.5"), 64)
+	if err != nil || weekendMultiplier <= 0 {
+		log.Fatal("Invalid OVERTIME_WEEKEND_MULTIPLIER: must be a positive number")
+	}
+	holidayMultiplier, err := strconv.ParseFloat(envOrDefault("OVERTIME_HOLIDAY_MULTIPLIER", "2"), 64)
+	if err != nil || holidayMultiplier <= 0 {
+		log.Fatal("Invalid OVERTIME_HOLIDAY_MULTIPLIER: must be a positive number")
+	}
+	attendanceConfig := &service.AttendanceConfig{
+		HolidayPolicy:      holidayPolicy,
+		GeofencePolicy:     geofencePolicy,
+		AbsenceCutoff:      time.Duration(cutoff.Hour())*time.Hour + time.Duration(cutoff.Minute())*time.Minute,
+		MaxSessionDuration: maxSession,
+		Breaks: service.BreakRules{
+			MaxMinutes:      maxBreak,
+			RequiredAfter:   time.Duration(breakRequiredAfter) * time.Minute,
+			RequiredMinutes: breakRequired,
+		},
+		Overtime: service.OvertimeRules{
+			DailyThreshold:    dailyThreshold,
+			WeeklyThreshold:   weeklyThreshold,
+			WeekendMultiplier: weekendMultiplier,
+			HolidayMultiplier: holidayMultiplier,
+		},
+	}
+
+	// Outgoing notifications such as password reset links
+	notify, err := newNotifier()
+	if err != nil {
+		log.Fatal("Invalid NOTIFIER:", err)
+	}
+	resetTTL, err := strconv.Atoi(envOrDefault("PASSWORD_RESET_TTL_MINUTES", "60"))
+	if err != nil {
+		log.Fatal("Invalid PASSWORD_RESET_TTL_MINUTES:", err)
+	}
+	passwordResetConfig := &service.PasswordResetConfig{
+		TokenTTL: time.Duration(resetTTL) * time.Minute,
+		URL:      os.Getenv("PASSWORD_RESET_URL"),
+	}
+
+	// Brute-force protection for the login endpoint
+	maxFailures, err := strconv.Atoi(envOrDefault("LOGIN_MAX_FAILURES", "5"))
+	if err != nil || maxFailures < 1 {
+		log.Fatal("Invalid LOGIN_MAX_FAILURES: must be a positive number")
+	}
+	ipMaxFailures, err := strconv.Atoi(envOrDefault("LOGIN_IP_MAX_FAILURES", "20"))
+	if err != nil || ipMaxFailures < 1 {
+		log.Fatal("Invalid LOGIN_IP_MAX_FAILURES: must be a positive number")
+	}
+	lockoutMinutes, err := strconv.Atoi(envOrDefault("LOGIN_LOCKOUT_MINUTES", "15"))
+	if err != nil || lockoutMinutes < 1 {
+		log.Fatal("Invalid LOGIN_LOCKOUT_MINUTES: must be a positive number")
+	}
+	loginThrottleConfig := &service.LoginThrottleConfig{
+		MaxFailures:     maxFailures,
+		IPMaxFailures:   ipMaxFailures,
+		LockoutDuration: time.Duration(lockoutMinutes) * time.Minute,
+		BaseDelay:       time.Second,
+	}
+
+	// Optional TOTP two-factor authentication
+	twoFactorConfig := &service.TwoFactorConfig{
+		Issuer:       envOrDefault("TOTP_ISSUER", "Absence"),
+		ChallengeTTL: 5 * time.Minute,
+	}
+
+	// Registration is closed unless explicitly opened; invitations always work
+	registrationMode := envOrDefault("REGISTRATION_MODE", "closed")
+	if registrationMode != "open" && registrationMode != "closed" {
+		log.Fatal("Invalid REGISTRATION_MODE: must be 'open' or 'closed'")
+	}
+	invitationTTL, err := hoursFromEnv("INVITATION_TTL_HOURS", 72)
+	if err != nil {
+		log.Fatal("Invalid INVITATION_TTL_HOURS:", err)
+	}
+	registrationConfig := &service.RegistrationConfig{
+		Open:          registrationMode == "open",
+		InvitationTTL: invitationTTL,
+		InvitationURL: os.Getenv("INVITATION_URL"),
+	}
+	for _, domain := range strings.Split(os.Getenv("REGISTRATION_ALLOWED_DOMAINS"), ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			registrationConfig.AllowedDomains = append(registrationConfig.AllowedDomains, domain)
+		}
 	}
-	tokenDuration := 24 * time.Hour // Token expires in 24 hours
-	jwtManager := jwt.NewJWTManager(secretKey, tokenDuration)
 
 	// Initialize API using wire
-	api, err := internal.InitializeAPI(db, jwtManager)
+	api, err := internal.InitializeAPI(db, jwtManager, attendanceConfig, passwordResetConfig, loginThrottleConfig, twoFactorConfig, registrationConfig, notify)
 	if err != nil {
 		log.Fatal("Failed to initialize API:", err)
 	}
 
+	// Background absence marking; disable on all but one instance when running replicas
+	if os.Getenv("ABSENCE_JOB_ENABLED") != "false" {
+		go api.AbsenceJob.Run(context.Background())
+	}
+
 	// Setup router
 	router := gin.Default()
 
+	// Per-IP login limits rely on the client IP, so only trust X-Forwarded-For from known proxies
+	var trustedProxies []string
+	if value := os.Getenv("TRUSTED_PROXIES"); value != "" {
+		for _, proxy := range strings.Split(value, ",") {
+			trustedProxies = append(trustedProxies, strings.TrimSpace(proxy))
+		}
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+
+	// Record the client IP of every change in the audit log
+	router.Use(middleware.AuditContext())
+
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Public keys for services verifying our tokens
+	router.GET("/.well-known/jwks.json", api.JWKSHandler.GetJWKS)
+
 	// Public routes
 	router.POST("/api/register", api.UserHandler.Register)
 	router.POST("/api/login", api.UserHandler.Login)
+	router.POST("/api/login/2fa", api.UserHandler.LoginTwoFactor)
+	router.POST("/api/token/refresh", api.UserHandler.RefreshToken)
+	router.POST("/api/password/forgot", api.UserHandler.ForgotPassword)
+	router.POST("/api/password/reset", api.UserHandler.ConfirmPasswordReset)
+	router.POST("/api/invitations/accept", api.InvitationHandler.AcceptInvitation)
 
 	// Protected routes
 	apiGroup := router.Group("/api")
 	apiGroup.Use(api.AuthMiddleware.AuthMiddleware())
 	{
+		apiGroup.POST("/logout", api.UserHandler.Logout)
+		apiGroup.POST("/password/change", api.UserHandler.ChangePassword)
+
+		// Two-factor authentication routes
+		twoFactor := apiGroup.Group("/2fa")
+		{
+			twoFactor.GET("", api.TwoFactorHandler.GetTwoFactorStatus)
+			twoFactor.POST("/setup", api.TwoFactorHandler.SetupTwoFactor)
+			twoFactor.POST("/enable", api.TwoFactorHandler.EnableTwoFactor)
+			twoFactor.POST("/disable", api.TwoFactorHandler.DisableTwoFactor)
+			twoFactor.POST("/recovery-codes", api.TwoFactorHandler.RegenerateRecoveryCodes)
+		}
+
 		// User routes
 		users := apiGroup.Group("/users")
 		{
-			users.GET("/:id", api.UserHandler.GetUser)
-			users.PUT("/:id", api.UserHandler.UpdateUser)
-			users.DELETE("/:id", api.UserHandler.DeleteUser)
-			users.GET("/:id/attendance", api.AttendanceHandler.GetUserAttendances)
+			users.GET("", middleware.RequirePermission(model.PermissionUsersRead), api.UserHandler.ListUsers)
+			users.GET("/:id", middleware.RequireSelfOrPermission("id", model.PermissionUsersRead), api.UserHandler.GetUser)
+			users.PUT("/:id", middleware.RequirePermission(model.PermissionUsersManage), api.UserHandler.UpdateUser)
+			users.DELETE("/:id", middleware.RequirePermission(model.PermissionUsersManage), api.UserHandler.DeleteUser)
+			users.POST("/:id/restore", middleware.RequirePermission(model.PermissionUsersManage), api.UserHandler.RestoreUser)
+			users.POST("/:id/activate", middleware.RequirePermission(model.PermissionUsersManage), api.UserHandler.ActivateUser)
+			users.POST("/:id/deactivate", middleware.RequirePermission(model.PermissionUsersManage), api.UserHandler.DeactivateUser)
+			users.GET("/:id/attendance", middleware.RequireSelfOrPermission("id", model.PermissionAttendanceRead), api.AttendanceHandler.GetUserAttendances)
+			users.GET("/:id/leaves", middleware.RequireSelfOrPermission("id", model.PermissionLeavesRead), api.LeaveHandler.GetUserLeaves)
+			users.GET("/:id/employee-details", middleware.RequireSelfOrPermission("id", model.PermissionUsersRead), api.EmployeeDetailHandler.GetEmployeeDetail)
+			users.PUT("/:id/employee-details", middleware.RequirePermission(model.PermissionUsersManage), api.EmployeeDetailHandler.SaveEmployeeDetail)
+			users.DELETE("/:id/employee-details", middleware.RequirePermission(model.PermissionUsersManage), api.EmployeeDetailHandler.DeleteEmployeeDetail)
+			users.PUT("/:id/role", middleware.RequirePermission(model.PermissionRolesManage), api.RoleHandler.AssignRole)
+			users.PUT("/:id/password", middleware.RequirePermission(model.PermissionUsersManage), api.UserHandler.ResetUserPassword)
+			users.DELETE("/:id/2fa", middleware.RequirePermission(model.PermissionUsersManage), api.TwoFactorHandler.ResetTwoFactor)
 		}
 
 		// Attendance routes
 		attendance := apiGroup.Group("/attendance")
 		{
+			manageAttendance := middleware.RequirePermission(model.PermissionAttendanceManage)
+
+			attendance.POST("", manageAttendance, api.AttendanceHandler.CreateAttendance)
 			attendance.POST("/check-in", api.AttendanceHandler.CheckIn)
 			attendance.POST("/check-out", api.AttendanceHandler.CheckOut)
+			attendance.POST("/start-break", api.AttendanceHandler.StartBreak)
+			attendance.POST("/end-break", api.AttendanceHandler.EndBreak)
+			attendance.POST("/bulk", manageAttendance, api.AttendanceHandler.BulkUpdateAttendance)
 			attendance.GET("/:id", api.AttendanceHandler.GetAttendance)
+			attendance.PUT("/:id", manageAttendance, api.AttendanceHandler.UpdateAttendance)
+			attendance.DELETE("/:id", manageAttendance, api.AttendanceHandler.DeleteAttendance)
+			attendance.POST("/:id/restore", manageAttendance, api.AttendanceHandler.RestoreAttendance)
+		}
+
+		// Attendance correction routes
+		corrections := apiGroup.Group("/attendance-corrections")
+		{
+			// Department-scoped approvers are further limited to their own department by the correction service
+			canDecideCorrection := middleware.RequirePermission(model.PermissionAttendanceApprove, model.PermissionAttendanceApproveDepartment)
+
+			corrections.POST("", api.AttendanceCorrectionHandler.SubmitCorrection)
+			corrections.GET("", api.AttendanceCorrectionHandler.GetCorrections)
+			corrections.GET("/:id", api.AttendanceCorrectionHandler.GetCorrection)
+			corrections.POST("/:id/approve", canDecideCorrection, api.AttendanceCorrectionHandler.ApproveCorrection)
+			corrections.POST("/:id/reject", canDecideCorrection, api.AttendanceCorrectionHandler.RejectCorrection)
+			corrections.POST("/:id/cancel", api.AttendanceCorrectionHandler.CancelCorrection)
+		}
+
+		// Leave routes
+		leaves := apiGroup.Group("/leaves")
+		{
+			// Department-scoped approvers are further limited to their own department by the leave service
+			canDecideLeave := middleware.RequirePermission(model.PermissionLeavesApprove, model.PermissionLeavesApproveDepartment)
+
+			leaves.GET("/types", api.LeaveHandler.GetLeaveTypes)
+			leaves.POST("/types", middleware.RequirePermission(model.PermissionLeaveTypesManage), api.LeaveHandler.CreateLeaveType)
+			leaves.POST("", api.LeaveHandler.SubmitLeave)
+			leaves.GET("", api.LeaveHandler.GetLeaves)
+			leaves.GET("/:id", api.LeaveHandler.GetLeave)
+			leaves.POST("/:id/approve", canDecideLeave, api.LeaveHandler.ApproveLeave)
+			leaves.POST("/:id/reject", canDecideLeave, api.LeaveHandler.RejectLeave)
+			leaves.POST("/:id/cancel", api.LeaveHandler.CancelLeave)
+		}
+
+		// Department routes
+		departments := apiGroup.Group("/departments")
+		{
+			manageDepartments := middleware.RequirePermission(model.PermissionDepartmentsManage)
+
+			departments.GET("", api.DepartmentHandler.GetDepartments)
+			departments.POST("", manageDepartments, api.DepartmentHandler.CreateDepartment)
+			departments.GET("/:id", api.DepartmentHandler.GetDepartment)
+			departments.PUT("/:id", manageDepartments, api.DepartmentHandler.UpdateDepartment)
+			departments.DELETE("/:id", manageDepartments, api.DepartmentHandler.DeleteDepartment)
+			departments.GET("/:id/members", middleware.RequirePermission(model.PermissionUsersRead, model.PermissionDepartmentsManage), api.DepartmentHandler.GetDepartmentMembers)
+			departments.PUT("/:id/members/:user_id", manageDepartments, api.DepartmentHandler.AssignMember)
+			departments.DELETE("/:id/members/:user_id", manageDepartments, api.DepartmentHandler.RemoveMember)
+			departments.GET("/:id/schedules", api.WorkScheduleHandler.GetDepartmentSchedules)
+			departments.PUT("/:id/schedules/:day", manageDepartments, api.WorkScheduleHandler.SaveSchedule)
+			departments.DELETE("/:id/schedules/:day", manageDepartments, api.WorkScheduleHandler.DeleteSchedule)
+		}
+
+		// Holiday routes
+		holidays := apiGroup.Group("/holidays")
+		{
+			manageHolidays := middleware.RequirePermission(model.PermissionHolidaysManage)
+
+			holidays.GET("", api.HolidayHandler.GetHolidays)
+			holidays.POST("", manageHolidays, api.HolidayHandler.CreateHoliday)
+			holidays.POST("/import", manageHolidays, api.HolidayHandler.ImportHolidays)
+			holidays.GET("/:id", api.HolidayHandler.GetHoliday)
+			holidays.PUT("/:id", manageHolidays, api.HolidayHandler.UpdateHoliday)
+			holidays.DELETE("/:id", manageHolidays, api.HolidayHandler.DeleteHoliday)
+		}
+
+		// Role routes
+		roles := apiGroup.Group("/roles")
+		roles.Use(middleware.RequirePermission(model.PermissionRolesManage))
+		{
+			roles.GET("", api.RoleHandler.GetRoles)
+			roles.GET("/permissions", api.RoleHandler.GetPermissions)
+			roles.POST("", api.RoleHandler.CreateRole)
+			roles.PUT("/:id", api.RoleHandler.UpdateRole)
+			roles.DELETE("/:id", api.RoleHandler.DeleteRole)
+		}
+
+		// Invitation routes
+		invitations := apiGroup.Group("/invitations")
+		invitations.Use(middleware.RequirePermission(model.PermissionUsersManage))
+		{
+			invitations.GET("", api.InvitationHandler.GetInvitations)
+			invitations.POST("", api.InvitationHandler.CreateInvitation)
+			invitations.DELETE("/:id", api.InvitationHandler.RevokeInvitation)
+		}
+
+		// Login attempt routes
+		loginAttempts := apiGroup.Group("/login-attempts")
+		loginAttempts.Use(middleware.RequirePermission(model.PermissionUsersManage))
+		{
+			loginAttempts.GET("", api.LoginAttemptHandler.GetLoginAttempts)
+			loginAttempts.POST("/unlock", api.LoginAttemptHandler.UnlockLogin)
+		}
+
+		// Audit log routes
+		apiGroup.GET("/audit-logs", middleware.RequirePermission(model.PermissionAuditRead), api.AuditLogHandler.GetAuditLogs)
+
+		// Geofence routes
+		geofences := apiGroup.Group("/geofences")
+		{
+			manageGeofences := middleware.RequirePermission(model.PermissionGeofencesManage)
+
+			geofences.GET("", api.GeofenceHandler.GetGeofences)
+			geofences.POST("", manageGeofences, api.GeofenceHandler.CreateGeofence)
+			geofences.PUT("/:id", manageGeofences, api.GeofenceHandler.UpdateGeofence)
+			geofences.DELETE("/:id", manageGeofences, api.GeofenceHandler.DeleteGeofence)
 		}
 	}
 
@@ -116,3 +422,62 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// envOrDefault returns the environment variable, or the fallback when it is unset
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// newNotifier picks how notifications are delivered from NOTIFIER: "log" (default), "file" or "smtp"
+func newNotifier() (notifier.Notifier, error) {
+	switch kind := envOrDefault("NOTIFIER", "log"); kind {
+	case "log":
+		return notifier.NewLogNotifier(), nil
+	case "file":
+		return notifier.NewFileNotifier(envOrDefault("NOTIFIER_FILE", "notifications.log")), nil
+	case "smtp":
+		config := notifier.SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     envOrDefault("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		}
+		if config.Host == "" || config.From == "" {
+			return nil, errors.New("SMTP_HOST and SMTP_FROM are required for the smtp notifier")
+		}
+		return notifier.NewSMTPNotifier(config), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", kind)
+	}
+}
+
+// hoursFromEnv reads a whole number of hours from the environment, falling back to a default when unset
+func hoursFromEnv(key string, fallback int) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return time.Duration(fallback) * time.Hour, nil
+	}
+	hours, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(hours) * time.Hour, nil
+}
+
+// loadSigningKeys uses the RS256/EdDSA keys listed in JWT_KEYS_FILE when set, and the shared
+// JWT_SECRET_KEY (HS256) otherwise
+func loadSigningKeys() (*jwt.KeySet, error) {
+	if manifest := os.Getenv("JWT_KEYS_FILE"); manifest != "" {
+		return jwt.LoadKeySet(manifest)
+	}
+
+	secretKey := os.Getenv("JWT_SECRET_KEY")
+	if secretKey == "" {
+		return nil, errors.New("either JWT_KEYS_FILE or JWT_SECRET_KEY must be set")
+	}
+	return jwt.NewKeySet(jwt.NewHMACKey("default", []byte(secretKey)))
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"absence/internal/middleware"
+	"absence/internal/model"
+	"absence/internal/model/request"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttendanceCorrectionHandler struct {
+	correctionService service.AttendanceCorrectionService
+}
+
+func NewAttendanceCorrectionHandler(correctionService service.AttendanceCorrectionService) *AttendanceCorrectionHandler {
+	return &AttendanceCorrectionHandler{
+		correctionService: correctionService,
+	}
+}
+
+// SubmitCorrection godoc
+// @Summary Submit attendance correction
+// @Description Propose corrected check-in and/or check-out times for one of your own attendance records
+// @Tags attendance-corrections
+// @Accept json
+// @Produce json
+// @Param request body request.SubmitAttendanceCorrectionRequest true "Correction details"
+// @Success 201 {object} response.Response{data=model.AttendanceCorrection} "Attendance correction submitted successfully"
+// @Failure 400 {object} response.Response "Invalid input or times"
+// @Failure 404 {object} response.Response "Attendance not found"
+// @Failure 409 {object} response.Response "Attendance already has a pending correction or would overlap another session"
+// @Security BearerAuth
+// @Router /attendance-corrections [post]
+func (h *AttendanceCorrectionHandler) SubmitCorrection(c *gin.Context) {
+	var req request.SubmitAttendanceCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	correction := &model.AttendanceCorrection{
+		AttendanceID: req.AttendanceID,
+		UserID:       userID.(uint),
+		CheckIn:      req.CheckIn,
+		CheckOut:     req.CheckOut,
+		Reason:       req.Reason,
+	}
+
+	if err := h.correctionService.Submit(c.Request.Context(), correction); err != nil {
+		switch {
+		case errors.Is(err, service.ErrAttendanceNotFound):
+			response.Error(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrCorrectionPending), errors.Is(err, service.ErrAttendanceOverlap):
+			response.Error(c, http.StatusConflict, err.Error())
+		case isInvalidAttendanceTimes(err), errors.Is(err, service.ErrCorrectionEmpty):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Attendance correction submitted successfully", correction)
+}
+
+// GetCorrections godoc
+// @Summary Get attendance corrections
+// @Description Users with attendance:read or attendance:approve get all corrections and supervisors those of their department, optionally filtered by status; everyone else gets their own
+// @Tags attendance-corrections
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (pending, approved, rejected, cancelled)"
+// @Success 200 {object} response.Response{data=[]model.AttendanceCorrection} "Attendance corrections retrieved successfully"
+// @Failure 500 {object} response.Response "Server error"
+// @Security BearerAuth
+// @Router /attendance-corrections [get]
+func (h *AttendanceCorrectionHandler) GetCorrections(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	corrections, err := h.correctionService.GetVisibleByStatus(c.Request.Context(), userID.(uint), correctionViewScope(c), c.Query("status"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attendance corrections retrieved successfully", corrections)
+}
+
+// GetCorrection godoc
+// @Summary Get attendance correction by ID
+// @Description Get attendance correction details by ID
+// @Tags attendance-corrections
+// @Accept json
+// @Produce json
+// @Param id path int true "Attendance correction ID"
+// @Success 200 {object} response.Response{data=model.AttendanceCorrection} "Attendance correction retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid attendance correction ID"
+// @Failure 404 {object} response.Response "Attendance correction not found"
+// @Security BearerAuth
+// @Router /attendance-corrections/{id} [get]
+func (h *AttendanceCorrectionHandler) GetCorrection(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid attendance correction ID")
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	// Corrections outside the viewer's scope are reported as missing
+	correction, err := h.correctionService.GetVisible(c.Request.Context(), uint(id), userID.(uint), correctionViewScope(c))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Attendance correction not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attendance correction retrieved successfully", correction)
+}
+
+// ApproveCorrection godoc
+// @Summary Approve attendance correction
+// @Description Apply a pending correction to its attendance, keeping the replaced times on the correction (attendance:approve, or attendance:approve:department for the approver's own department)
+// @Tags attendance-corrections
+// @Accept json
+// @Produce json
+// @Param id path int true "Attendance correction ID"
+// @Success 200 {object} response.Response{data=model.AttendanceCorrection} "Attendance correction approved"
+// @Failure 400 {object} response.Response "Invalid attendance correction ID or times"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Attendance correction not found"
+// @Failure 409 {object} response.Response "Attendance correction is no longer pending or would overlap another session"
+// @Security BearerAuth
+// @Router /attendance-corrections/{id}/approve [post]
+func (h *AttendanceCorrectionHandler) ApproveCorrection(c *gin.Context) {
+	h.decide(c, h.correctionService.Approve, "Attendance correction approved")
+}
+
+// RejectCorrection godoc
+// @Summary Reject attendance correction
+// @Description Reject a pending correction (attendance:approve, or attendance:approve:department for the approver's own department)
+// @Tags attendance-corrections
+// @Accept json
+// @Produce json
+// @Param id path int true "Attendance correction ID"
+// @Success 200 {object} response.Response{data=model.AttendanceCorrection} "Attendance correction rejected"
+// @Failure 400 {object} response.Response "Invalid attendance correction ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Attendance correction not found"
+// @Failure 409 {object} response.Response "Attendance correction is no longer pending"
+// @Security BearerAuth
+// @Router /attendance-corrections/{id}/reject [post]
+func (h *AttendanceCorrectionHandler) RejectCorrection(c *gin.Context) {
+	h.decide(c, h.correctionService.Reject, "Attendance correction rejected")
+}
+
+func (h *AttendanceCorrectionHandler) decide(c *gin.Context, decide func(ctx context.Context, id, approverID uint, scope service.ApprovalScope) (*model.AttendanceCorrection, error), message string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid attendance correction ID")
+		return
+	}
+
+	approverID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	scope := service.ResolveApprovalScope(c.GetStringSlice("permissions"), model.PermissionAttendanceApprove, model.PermissionAttendanceApproveDepartment)
+	correction, err := decide(c.Request.Context(), uint(id), approverID.(uint), scope)
+	if err != nil {
+		h.handleTransitionError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, message, correction)
+}
+
+// CancelCorrection godoc
+// @Summary Cancel attendance correction
+// @Description Withdraw one of your own pending corrections
+// @Tags attendance-corrections
+// @Accept json
+// @Produce json
+// @Param id path int true "Attendance correction ID"
+// @Success 200 {object} response.Response{data=model.AttendanceCorrection} "Attendance correction cancelled"
+// @Failure 400 {object} response.Response "Invalid attendance correction ID"
+// @Failure 404 {object} response.Response "Attendance correction not found"
+// @Failure 409 {object} response.Response "Attendance correction is no longer pending"
+// @Security BearerAuth
+// @Router /attendance-corrections/{id}/cancel [post]
+func (h *AttendanceCorrectionHandler) CancelCorrection(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid attendance correction ID")
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	correction, err := h.correctionService.Cancel(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		h.handleTransitionError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attendance correction cancelled", correction)
+}
+
+func (h *AttendanceCorrectionHandler) handleTransitionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCorrectionNotFound), errors.Is(err, service.ErrCorrectionForbidden):
+		response.Error(c, http.StatusNotFound, "Attendance correction not found")
+	case errors.Is(err, service.ErrCorrectionSelfApproval), errors.Is(err, service.ErrCorrectionOutOfScope):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrCorrectionNotPending), errors.Is(err, service.ErrAttendanceOverlap):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrAttendanceNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case isInvalidAttendanceTimes(err):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// correctionViewScope lets attendance:read see every correction in addition to the approvers
+func correctionViewScope(c *gin.Context) service.ApprovalScope {
+	if middleware.HasPermission(c, model.PermissionAttendanceRead) {
+		return service.ApprovalScopeAll
+	}
+	return service.ResolveApprovalScope(c.GetStringSlice("permissions"), model.PermissionAttendanceApprove, model.PermissionAttendanceApproveDepartment)
+}
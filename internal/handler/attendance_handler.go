@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"absence/internal/middleware"
+	"absence/internal/model"
 	"absence/internal/model/request"
 	"absence/internal/service"
 	"absence/pkg/response"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -23,13 +27,14 @@ func NewAttendanceHandler(attendanceService service.AttendanceService) *Attendan
 
 // CheckIn godoc
 // @Summary Check-in attendance
-// @Description Record user check-in for the day
+// @Description Start a check-in session; a day can have several sessions, e.g. a split shift
 // @Tags attendance
 // @Accept json
 // @Produce json
 // @Param request body request.CheckInRequest true "Check-in details"
 // @Success 200 {object} response.Response{data=model.Attendance} "Check-in successful"
 // @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Outside the allowed office areas"
 // @Failure 409 {object} response.Response "Already checked in"
 // @Security BearerAuth
 // @Router /attendance/check-in [post]
@@ -47,24 +52,40 @@ func (h *AttendanceHandler) CheckIn(c *gin.Context) {
 		return
 	}
 
-	if err := h.attendanceService.CheckIn(c.Request.Context(), userID.(uint), req.Location); err != nil {
-		response.Error(c, http.StatusConflict, err.Error())
+	attendance, err := h.attendanceService.CheckIn(c.Request.Context(), service.AttendanceCommand{
+		UserID:   userID.(uint),
+		Location: req.Location,
+		Point:    geoPoint(req.Latitude, req.Longitude),
+		Notes:    req.Notes,
+		Device:   req.Device,
+		Source:   req.Source,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrLocationRequired):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrOutsideGeofence):
+			response.Error(c, http.StatusForbidden, err.Error())
+		default:
+			response.Error(c, http.StatusConflict, err.Error())
+		}
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Check-in successful", nil)
+	response.Success(c, http.StatusOK, "Check-in successful", attendance)
 }
 
 // CheckOut godoc
 // @Summary Check-out attendance
-// @Description Record user check-out for the day
+// @Description End the open check-in session, also when it started the day before
 // @Tags attendance
 // @Accept json
 // @Produce json
 // @Param request body request.CheckOutRequest true "Check-out details"
 // @Success 200 {object} response.Response{data=model.Attendance} "Check-out successful"
 // @Failure 400 {object} response.Response "Invalid input"
-// @Failure 404 {object} response.Response "No check-in record found"
+// @Failure 403 {object} response.Response "Outside the allowed office areas"
+// @Failure 404 {object} response.Response "No open check-in found"
 // @Security BearerAuth
 // @Router /attendance/check-out [post]
 func (h *AttendanceHandler) CheckOut(c *gin.Context) {
@@ -81,12 +102,78 @@ func (h *AttendanceHandler) CheckOut(c *gin.Context) {
 		return
 	}
 
-	if err := h.attendanceService.CheckOut(c.Request.Context(), userID.(uint), req.Location); err != nil {
-		response.Error(c, http.StatusNotFound, err.Error())
+	attendance, err := h.attendanceService.CheckOut(c.Request.Context(), service.AttendanceCommand{
+		UserID:   userID.(uint),
+		Location: req.Location,
+		Point:    geoPoint(req.Latitude, req.Longitude),
+		Notes:    req.Notes,
+		Device:   req.Device,
+		Source:   req.Source,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrLocationRequired):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrOutsideGeofence):
+			response.Error(c, http.StatusForbidden, err.Error())
+		default:
+			response.Error(c, http.StatusNotFound, err.Error())
+		}
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Check-out successful", nil)
+	response.Success(c, http.StatusOK, "Check-out successful", attendance)
+}
+
+// StartBreak godoc
+// @Summary Start break
+// @Description Start a break in the open check-in session
+// @Tags attendance
+// @Produce json
+// @Success 200 {object} response.Response{data=model.Attendance} "Break started"
+// @Failure 404 {object} response.Response "No open check-in found"
+// @Failure 409 {object} response.Response "Already on a break"
+// @Security BearerAuth
+// @Router /attendance/start-break [post]
+func (h *AttendanceHandler) StartBreak(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	attendance, err := h.attendanceService.StartBreak(c.Request.Context(), userID.(uint))
+	if err != nil {
+		h.handleBreakError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Break started", attendance)
+}
+
+// EndBreak godoc
+// @Summary End break
+// @Description End the running break of the open check-in session
+// @Tags attendance
+// @Produce json
+// @Success 200 {object} response.Response{data=model.Attendance} "Break ended"
+// @Failure 404 {object} response.Response "No open check-in or running break found"
+// @Security BearerAuth
+// @Router /attendance/end-break [post]
+func (h *AttendanceHandler) EndBreak(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	attendance, err := h.attendanceService.EndBreak(c.Request.Context(), userID.(uint))
+	if err != nil {
+		h.handleBreakError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Break ended", attendance)
 }
 
 // GetAttendance godoc
@@ -108,18 +195,30 @@ func (h *AttendanceHandler) GetAttendance(c *gin.Context) {
 		return
 	}
 
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	attendance, err := h.attendanceService.GetAttendanceByID(c.Request.Context(), uint(id))
 	if err != nil {
 		response.Error(c, http.StatusNotFound, "Attendance not found")
 		return
 	}
 
+	// Without attendance:read users only see their own records; hide the rest as if they did not exist
+	if !middleware.HasPermission(c, model.PermissionAttendanceRead) && attendance.UserID != userID.(uint) {
+		response.Error(c, http.StatusNotFound, "Attendance not found")
+		return
+	}
+
 	response.Success(c, http.StatusOK, "Attendance details retrieved successfully", attendance)
 }
 
 // GetUserAttendances godoc
 // @Summary Get user attendances
-// @Description Get attendance history for a user
+// @Description Get attendance history for a user with regular and overtime minutes per record and totals for the period
 // @Tags attendance
 // @Accept json
 // @Produce json
@@ -127,7 +226,7 @@ func (h *AttendanceHandler) GetAttendance(c *gin.Context) {
 // @Param date query string false "Filter by date (YYYY-MM-DD)"
 // @Param month query string false "Filter by month (YYYY-MM)"
 // @Param year query string false "Filter by year (YYYY)"
-// @Success 200 {object} response.Response{data=[]model.Attendance} "User attendances retrieved successfully"
+// @Success 200 {object} response.Response{data=[]model.Attendance,meta=service.WorkedHours} "User attendances with the worked time totals of the period"
 // @Failure 400 {object} response.Response "Invalid user ID or date format"
 // @Security BearerAuth
 // @Router /users/{id}/attendance [get]
@@ -147,21 +246,21 @@ func (h *AttendanceHandler) GetUserAttendances(c *gin.Context) {
 	year := c.Query("year")
 
 	if date != "" {
-		startDate, err = time.Parse("2006-01-02", date)
+		startDate, err = time.ParseInLocation("2006-01-02", date, time.Local)
 		if err != nil {
 			response.Error(c, http.StatusBadRequest, "Invalid date format")
 			return
 		}
 		endDate = startDate.Add(24 * time.Hour)
 	} else if month != "" {
-		startDate, err = time.Parse("2006-01", month)
+		startDate, err = time.ParseInLocation("2006-01", month, time.Local)
 		if err != nil {
 			response.Error(c, http.StatusBadRequest, "Invalid month format")
 			return
 		}
 		endDate = startDate.AddDate(0, 1, 0)
 	} else if year != "" {
-		startDate, err = time.Parse("2006", year)
+		startDate, err = time.ParseInLocation("2006", year, time.Local)
 		if err != nil {
 			response.Error(c, http.StatusBadRequest, "Invalid year format")
 			return
@@ -169,15 +268,270 @@ func (h *AttendanceHandler) GetUserAttendances(c *gin.Context) {
 		endDate = startDate.AddDate(1, 0, 0)
 	} else {
 		// Default to current month
-		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 		endDate = startDate.AddDate(0, 1, 0)
 	}
 
-	attendances, err := h.attendanceService.GetUserAttendances(c.Request.Context(), uint(userID), startDate, endDate)
+	attendances, totals, err := h.attendanceService.GetUserAttendances(c.Request.Context(), uint(userID), startDate, endDate)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	response.Success(c, http.StatusOK, "User attendances retrieved successfully", attendances)
+	response.SuccessWithMeta(c, http.StatusOK, "User attendances retrieved successfully", attendances, totals)
+}
+
+// CreateAttendance godoc
+// @Summary Create attendance
+// @Description Enter an attendance record for any user and day (requires attendance:manage)
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Param request body request.CreateAttendanceRequest true "Attendance details"
+// @Success 201 {object} response.Response{data=model.Attendance} "Attendance created successfully"
+// @Failure 400 {object} response.Response "Invalid input or times"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "User not found"
+// @Failure 409 {object} response.Response "Day already has a record or the session overlaps another one"
+// @Security BearerAuth
+// @Router /attendance [post]
+func (h *AttendanceHandler) CreateAttendance(c *gin.Context) {
+	var req request.CreateAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid date format")
+		return
+	}
+
+	attendance, err := h.attendanceService.CreateManual(c.Request.Context(), req.UserID, day, service.AttendanceEdit{
+		CheckIn:  req.CheckIn,
+		CheckOut: req.CheckOut,
+		Status:   req.Status,
+	})
+	if err != nil {
+		h.handleEditError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Attendance created successfully", attendance)
+}
+
+// UpdateAttendance godoc
+// @Summary Update attendance
+// @Description Replace the check-in and check-out times of an attendance record and reclassify it (requires attendance:manage)
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Param id path int true "Attendance ID"
+// @Param request body request.UpdateAttendanceRequest true "New times"
+// @Success 200 {object} response.Response{data=model.Attendance} "Attendance updated successfully"
+// @Failure 400 {object} response.Response "Invalid input or times"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Attendance not found"
+// @Failure 409 {object} response.Response "Session overlaps another one"
+// @Security BearerAuth
+// @Router /attendance/{id} [put]
+func (h *AttendanceHandler) UpdateAttendance(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid attendance ID")
+		return
+	}
+
+	var req request.UpdateAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	attendance, err := h.attendanceService.Edit(c.Request.Context(), uint(id), service.AttendanceEdit{
+		CheckIn:  req.CheckIn,
+		CheckOut: req.CheckOut,
+		Status:   req.Status,
+	})
+	if err != nil {
+		h.handleEditError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attendance updated successfully", attendance)
+}
+
+// DeleteAttendance godoc
+// @Summary Delete attendance
+// @Description Delete an attendance record; it stays in the database and the audit log (requires attendance:manage)
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Param id path int true "Attendance ID"
+// @Success 200 {object} response.Response "Attendance deleted successfully"
+// @Failure 400 {object} response.Response "Invalid attendance ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Attendance not found"
+// @Security BearerAuth
+// @Router /attendance/{id} [delete]
+func (h *AttendanceHandler) DeleteAttendance(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid attendance ID")
+		return
+	}
+
+	if err := h.attendanceService.Delete(c.Request.Context(), uint(id)); err != nil {
+		h.handleEditError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attendance deleted successfully", nil)
+}
+
+// RestoreAttendance godoc
+// @Summary Restore attendance
+// @Description Bring back a deleted attendance record unless its day has been filled in again (requires attendance:manage)
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Param id path int true "Attendance ID"
+// @Success 200 {object} response.Response{data=model.Attendance} "Attendance restored successfully"
+// @Failure 400 {object} response.Response "Invalid attendance ID or times"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Attendance not found"
+// @Failure 409 {object} response.Response "Attendance is not deleted or conflicts with the records of its day"
+// @Security BearerAuth
+// @Router /attendance/{id}/restore [post]
+func (h *AttendanceHandler) RestoreAttendance(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid attendance ID")
+		return
+	}
+
+	attendance, err := h.attendanceService.Restore(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrAttendanceNotDeleted) {
+			response.Error(c, http.StatusConflict, err.Error())
+		} else {
+			h.handleEditError(c, err)
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attendance restored successfully", attendance)
+}
+
+// BulkUpdateAttendance godoc
+// @Summary Bulk update attendance
+// @Description Apply the same times or status to one day of many users, e.g. mark a whole department present, creating missing records. Users who already checked in that day are skipped and listed (requires attendance:manage)
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Param request body request.BulkAttendanceRequest true "Users, day and times"
+// @Success 200 {object} response.Response "Attendance updated successfully"
+// @Failure 400 {object} response.Response "Invalid input or times"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 500 {object} response.Response "Some records could not be saved"
+// @Security BearerAuth
+// @Router /attendance/bulk [post]
+func (h *AttendanceHandler) BulkUpdateAttendance(c *gin.Context) {
+	var req request.BulkAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid date format")
+		return
+	}
+
+	edit := service.AttendanceEdit{Status: req.Status}
+	if req.CheckIn != "" {
+		checkIn, err := timeOnDay(day, req.CheckIn)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid check-in time, expected HH:MM")
+			return
+		}
+		edit.CheckIn = &checkIn
+	}
+	if req.CheckOut != "" {
+		checkOut, err := timeOnDay(day, req.CheckOut)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid check-out time, expected HH:MM")
+			return
+		}
+		if edit.CheckIn != nil && !checkOut.After(*edit.CheckIn) {
+			checkOut = checkOut.AddDate(0, 0, 1)
+		}
+		edit.CheckOut = &checkOut
+	}
+
+	selection := service.AttendanceSelection{UserIDs: req.UserIDs, DepartmentID: req.DepartmentID}
+	result, err := h.attendanceService.BulkEdit(c.Request.Context(), selection, day, edit)
+	if err != nil {
+		switch {
+		case isInvalidAttendanceTimes(err), errors.Is(err, service.ErrInvalidMissingStatus), errors.Is(err, service.ErrNoUsersSelected):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			// Records of the other users were saved
+			response.Error(c, http.StatusInternalServerError, fmt.Sprintf("Updated %d records, but some failed: %v", result.Updated, err))
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attendance updated successfully", gin.H{"updated": result.Updated, "skipped": result.Skipped})
+}
+
+func (h *AttendanceHandler) handleBreakError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrNoOpenCheckIn), errors.Is(err, service.ErrNoOpenBreak):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrBreakInProgress):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (h *AttendanceHandler) handleEditError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAttendanceNotFound), errors.Is(err, service.ErrUserNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrAttendanceExists), errors.Is(err, service.ErrAttendanceOverlap):
+		response.Error(c, http.StatusConflict, err.Error())
+	case isInvalidAttendanceTimes(err), errors.Is(err, service.ErrInvalidMissingStatus):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// timeOnDay combines a day with a time of day in HH:MM format
+func timeOnDay(day time.Time, clock string) (time.Time, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), parsed.Hour(), parsed.Minute(), 0, 0, day.Location()), nil
+}
+
+// geoPoint builds a service location from optional request coordinates
+func geoPoint(latitude, longitude *float64) *service.GeoPoint {
+	if latitude == nil || longitude == nil {
+		return nil
+	}
+	return &service.GeoPoint{Latitude: *latitude, Longitude: *longitude}
+}
+
+// isInvalidAttendanceTimes reports whether err rejects edited check-in or check-out times
+func isInvalidAttendanceTimes(err error) bool {
+	return errors.Is(err, service.ErrCheckOutBeforeCheckIn) ||
+		errors.Is(err, service.ErrCheckOutWithoutCheckIn) ||
+		errors.Is(err, service.ErrCheckInOutsideWorkday) ||
+		errors.Is(err, service.ErrAttendanceInFuture)
 }
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"absence/internal/model"
+	"absence/internal/service"
+
+	"gorm.io/gorm"
+)
+
+// attendanceServiceStub serves a fixed set of records; other methods are not expected to be called
+type attendanceServiceStub struct {
+	service.AttendanceService
+	records map[uint]model.Attendance
+}
+
+func (s *attendanceServiceStub) GetAttendanceByID(_ context.Context, id uint) (*model.Attendance, error) {
+	record, ok := s.records[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &record, nil
+}
+
+func TestGetAttendanceOwnership(t *testing.T) {
+	handler := NewAttendanceHandler(&attendanceServiceStub{records: map[uint]model.Attendance{
+		10: {ID: 10, UserID: 1},
+		20: {ID: 20, UserID: 2},
+	}})
+
+	tests := []struct {
+		name        string
+		target      string
+		permissions []string
+		want        int
+	}{
+		{"own record", "/attendance/10", nil, http.StatusOK},
+		{"another user's record", "/attendance/20", nil, http.StatusNotFound},
+		{"another user's record with unrelated permission", "/attendance/20", []string{model.PermissionLeavesRead}, http.StatusNotFound},
+		{"another user's record with attendance:read", "/attendance/20", []string{model.PermissionAttendanceRead}, http.StatusOK},
+		{"another user's record with all permissions", "/attendance/20", []string{model.PermissionAll}, http.StatusOK},
+		{"missing record", "/attendance/30", []string{model.PermissionAll}, http.StatusNotFound},
+		{"non-numeric id", "/attendance/abc", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := serve(t, "/attendance/:id", tt.target, authenticate(1, tt.permissions...), handler.GetAttendance)
+			if got.Code != tt.want {
+				t.Errorf("status = %d, want %d", got.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAttendanceWithoutUser(t *testing.T) {
+	handler := NewAttendanceHandler(&attendanceServiceStub{records: map[uint]model.Attendance{
+		10: {ID: 10, UserID: 1},
+	}})
+
+	got := serve(t, "/attendance/:id", "/attendance/10", handler.GetAttendance)
+	if got.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", got.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"absence/internal/model"
+	"absence/internal/repository"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditLogHandler struct {
+	auditLogService service.AuditLogService
+}
+
+func NewAuditLogHandler(auditLogService service.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{
+		auditLogService: auditLogService,
+	}
+}
+
+// GetAuditLogs godoc
+// @Summary Get audit log
+// @Description Get one page of recorded creates, updates and deletes, newest first (requires audit:read)
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param actor_id query int false "Only changes made by this user"
+// @Param entity query string false "Only changes to this table, e.g. users or attendances"
+// @Param entity_id query string false "Only changes to the row with this ID; use together with entity"
+// @Param action query string false "create, update or delete"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Entries per page (max 100)" default(20)
+// @Success 200 {object} response.Response{data=[]model.AuditLog,meta=response.Pagination} "Audit log retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid query parameter"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Security BearerAuth
+// @Router /audit-logs [get]
+func (h *AuditLogHandler) GetAuditLogs(c *gin.Context) {
+	filter := repository.AuditLogFilter{
+		Entity:   c.Query("entity"),
+		EntityID: c.Query("entity_id"),
+		Action:   c.Query("action"),
+		Page:     1,
+		PageSize: defaultPageSize,
+	}
+
+	if value := c.Query("actor_id"); value != "" {
+		actorID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid actor ID")
+			return
+		}
+		id := uint(actorID)
+		filter.ActorID = &id
+	}
+
+	switch filter.Action {
+	case "", model.AuditActionCreate, model.AuditActionUpdate, model.AuditActionDelete:
+	default:
+		response.Error(c, http.StatusBadRequest, "Invalid action")
+		return
+	}
+
+	if value := c.Query("from"); value != "" {
+		from, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid from date format")
+			return
+		}
+		filter.From = from
+	}
+	if value := c.Query("to"); value != "" {
+		to, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid to date format")
+			return
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	if value := c.Query("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			response.Error(c, http.StatusBadRequest, "Invalid page")
+			return
+		}
+		filter.Page = page
+	}
+	if value := c.Query("page_size"); value != "" {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil || pageSize < 1 || pageSize > maxPageSize {
+			response.Error(c, http.StatusBadRequest, "Invalid page size")
+			return
+		}
+		filter.PageSize = pageSize
+	}
+
+	entries, total, err := h.auditLogService.List(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, "Audit log retrieved successfully", entries, response.NewPagination(filter.Page, filter.PageSize, total))
+}
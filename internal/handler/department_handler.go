@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"absence/internal/model"
+	"absence/internal/model/request"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DepartmentHandler struct {
+	departmentService service.DepartmentService
+}
+
+func NewDepartmentHandler(departmentService service.DepartmentService) *DepartmentHandler {
+	return &DepartmentHandler{
+		departmentService: departmentService,
+	}
+}
+
+// CreateDepartment godoc
+// @Summary Create department
+// @Description Create a new department (requires departments:manage)
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Param department body request.DepartmentRequest true "Department details"
+// @Success 201 {object} response.Response{data=model.Department} "Department created successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 500 {object} response.Response "Server error"
+// @Security BearerAuth
+// @Router /departments [post]
+func (h *DepartmentHandler) CreateDepartment(c *gin.Context) {
+	var req request.DepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	department := &model.Department{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := h.departmentService.Create(c.Request.Context(), department); err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Department created successfully", department)
+}
+
+// GetDepartments godoc
+// @Summary Get departments
+// @Description Get all departments
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]model.Department} "Departments retrieved successfully"
+// @Failure 500 {object} response.Response "Server error"
+// @Security BearerAuth
+// @Router /departments [get]
+func (h *DepartmentHandler) GetDepartments(c *gin.Context) {
+	departments, err := h.departmentService.GetAll(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Departments retrieved successfully", departments)
+}
+
+// GetDepartment godoc
+// @Summary Get department by ID
+// @Description Get department details by ID
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Param id path int true "Department ID"
+// @Success 200 {object} response.Response{data=model.Department} "Department details retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid department ID"
+// @Failure 404 {object} response.Response "Department not found"
+// @Security BearerAuth
+// @Router /departments/{id} [get]
+func (h *DepartmentHandler) GetDepartment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid department ID")
+		return
+	}
+
+	department, err := h.departmentService.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Department not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Department details retrieved successfully", department)
+}
+
+// UpdateDepartment godoc
+// @Summary Update department
+// @Description Update department details (requires departments:manage)
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Param id path int true "Department ID"
+// @Param department body request.DepartmentRequest true "Department details"
+// @Success 200 {object} response.Response{data=model.Department} "Department updated successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Department not found"
+// @Failure 500 {object} response.Response "Server error"
+// @Security BearerAuth
+// @Router /departments/{id} [put]
+func (h *DepartmentHandler) UpdateDepartment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid department ID")
+		return
+	}
+
+	var req request.DepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	department := &model.Department{
+		ID:          uint(id),
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := h.departmentService.Update(c.Request.Context(), department); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Department updated successfully", department)
+}
+
+// DeleteDepartment godoc
+// @Summary Delete department
+// @Description Delete a department without members; deleted users count until they are removed (requires departments:manage)
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Param id path int true "Department ID"
+// @Success 200 {object} response.Response "Department deleted successfully"
+// @Failure 400 {object} response.Response "Invalid department ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Department not found"
+// @Failure 409 {object} response.Response "Department still has members"
+// @Security BearerAuth
+// @Router /departments/{id} [delete]
+func (h *DepartmentHandler) DeleteDepartment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid department ID")
+		return
+	}
+
+	if err := h.departmentService.Delete(c.Request.Context(), uint(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Department deleted successfully", nil)
+}
+
+// GetDepartmentMembers godoc
+// @Summary Get department members
+// @Description Get all users assigned to a department
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Param id path int true "Department ID"
+// @Success 200 {object} response.Response{data=[]model.User} "Department members retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid department ID"
+// @Failure 404 {object} response.Response "Department not found"
+// @Security BearerAuth
+// @Router /departments/{id}/members [get]
+func (h *DepartmentHandler) GetDepartmentMembers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid department ID")
+		return
+	}
+
+	members, err := h.departmentService.GetMembers(c.Request.Context(), uint(id))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Department members retrieved successfully", members)
+}
+
+// AssignMember godoc
+// @Summary Assign user to department
+// @Description Assign a user to a department, moving them out of any previous one (requires departments:manage)
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Param id path int true "Department ID"
+// @Param user_id path int true "User ID"
+// @Success 200 {object} response.Response "User assigned to department"
+// @Failure 400 {object} response.Response "Invalid ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Department or user not found"
+// @Security BearerAuth
+// @Router /departments/{id}/members/{user_id} [put]
+func (h *DepartmentHandler) AssignMember(c *gin.Context) {
+	h.changeMembership(c, h.departmentService.AssignUser, "User assigned to department")
+}
+
+// RemoveMember godoc
+// @Summary Remove user from department
+// @Description Remove a user, including a deleted one, from a department (requires departments:manage)
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Param id path int true "Department ID"
+// @Param user_id path int true "User ID"
+// @Success 200 {object} response.Response "User removed from department"
+// @Failure 400 {object} response.Response "Invalid ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "User is not a member of this department"
+// @Security BearerAuth
+// @Router /departments/{id}/members/{user_id} [delete]
+func (h *DepartmentHandler) RemoveMember(c *gin.Context) {
+	h.changeMembership(c, h.departmentService.RemoveUser, "User removed from department")
+}
+
+func (h *DepartmentHandler) changeMembership(c *gin.Context, change func(ctx context.Context, departmentID, userID uint) error, message string) {
+	departmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid department ID")
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := change(c.Request.Context(), uint(departmentID), uint(userID)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, message, nil)
+}
+
+func (h *DepartmentHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrDepartmentNotFound),
+		errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrUserNotInDept):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrDepartmentNotEmpty):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
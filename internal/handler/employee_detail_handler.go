@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"absence/internal/model"
+	"absence/internal/model/request"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmployeeDetailHandler struct {
+	employeeDetailService service.EmployeeDetailService
+}
+
+func NewEmployeeDetailHandler(employeeDetailService service.EmployeeDetailService) *EmployeeDetailHandler {
+	return &EmployeeDetailHandler{
+		employeeDetailService: employeeDetailService,
+	}
+}
+
+// GetEmployeeDetail godoc
+// @Summary Get employee profile
+// @Description Get the employee profile (employee number, position, join date) of a user
+// @Tags employee-details
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} response.Response{data=model.EmployeeDetail} "Employee profile retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 404 {object} response.Response "Employee profile not found"
+// @Security BearerAuth
+// @Router /users/{id}/employee-details [get]
+func (h *EmployeeDetailHandler) GetEmployeeDetail(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	detail, err := h.employeeDetailService.GetByUserID(c.Request.Context(), uint(userID))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Employee profile not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Employee profile retrieved successfully", detail)
+}
+
+// SaveEmployeeDetail godoc
+// @Summary Create or update employee profile
+// @Description Create or replace the employee profile of a user, optionally moving them to a department (requires users:manage)
+// @Tags employee-details
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param profile body request.EmployeeDetailRequest true "Employee profile"
+// @Success 200 {object} response.Response{data=model.EmployeeDetail} "Employee profile saved successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "User or department not found"
+// @Failure 409 {object} response.Response "Employee ID already in use"
+// @Security BearerAuth
+// @Router /users/{id}/employee-details [put]
+func (h *EmployeeDetailHandler) SaveEmployeeDetail(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req request.EmployeeDetailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	joinDate, err := time.ParseInLocation("2006-01-02", req.JoinDate, time.Local)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid join date format")
+		return
+	}
+
+	detail := &model.EmployeeDetail{
+		UserID:     uint(userID),
+		EmployeeID: req.EmployeeID,
+		Position:   req.Position,
+		JoinDate:   joinDate,
+	}
+
+	if err := h.employeeDetailService.Save(c.Request.Context(), detail, req.DepartmentID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrDepartmentNotFound):
+			response.Error(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrEmployeeIDTaken):
+			response.Error(c, http.StatusConflict, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Employee profile saved successfully", detail)
+}
+
+// DeleteEmployeeDetail godoc
+// @Summary Delete employee profile
+// @Description Delete the employee profile of a user (requires users:manage)
+// @Tags employee-details
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} response.Response "Employee profile deleted successfully"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Employee profile not found"
+// @Security BearerAuth
+// @Router /users/{id}/employee-details [delete]
+func (h *EmployeeDetailHandler) DeleteEmployeeDetail(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.employeeDetailService.Delete(c.Request.Context(), uint(userID)); err != nil {
+		response.Error(c, http.StatusNotFound, "Employee profile not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Employee profile deleted successfully", nil)
+}
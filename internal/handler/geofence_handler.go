@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"absence/internal/model"
+	"absence/internal/model/request"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GeofenceHandler struct {
+	geofenceService service.GeofenceService
+}
+
+func NewGeofenceHandler(geofenceService service.GeofenceService) *GeofenceHandler {
+	return &GeofenceHandler{
+		geofenceService: geofenceService,
+	}
+}
+
+// GetGeofences godoc
+// @Summary Get office geofences
+// @Description Get all office areas that check-ins are validated against
+// @Tags geofences
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]model.Geofence} "Geofences retrieved successfully"
+// @Failure 500 {object} response.Response "Server error"
+// @Security BearerAuth
+// @Router /geofences [get]
+func (h *GeofenceHandler) GetGeofences(c *gin.Context) {
+	geofences, err := h.geofenceService.GetAll(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Geofences retrieved successfully", geofences)
+}
+
+// CreateGeofence godoc
+// @Summary Create office geofence
+// @Description Add an office area that check-ins are validated against (requires geofences:manage)
+// @Tags geofences
+// @Accept json
+// @Produce json
+// @Param geofence body request.GeofenceRequest true "Geofence details"
+// @Success 201 {object} response.Response{data=model.Geofence} "Geofence created successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 500 {object} response.Response "Server error"
+// @Security BearerAuth
+// @Router /geofences [post]
+func (h *GeofenceHandler) CreateGeofence(c *gin.Context) {
+	var req request.GeofenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	geofence := &model.Geofence{
+		Name:         req.Name,
+		Latitude:     *req.Latitude,
+		Longitude:    *req.Longitude,
+		RadiusMeters: req.RadiusMeters,
+	}
+
+	if err := h.geofenceService.Create(c.Request.Context(), geofence); err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Geofence created successfully", geofence)
+}
+
+// UpdateGeofence godoc
+// @Summary Update office geofence
+// @Description Update an office area (requires geofences:manage)
+// @Tags geofences
+// @Accept json
+// @Produce json
+// @Param id path int true "Geofence ID"
+// @Param geofence body request.GeofenceRequest true "Geofence details"
+// @Success 200 {object} response.Response{data=model.Geofence} "Geofence updated successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Geofence not found"
+// @Security BearerAuth
+// @Router /geofences/{id} [put]
+func (h *GeofenceHandler) UpdateGeofence(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid geofence ID")
+		return
+	}
+
+	var req request.GeofenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	geofence := &model.Geofence{
+		ID:           uint(id),
+		Name:         req.Name,
+		Latitude:     *req.Latitude,
+		Longitude:    *req.Longitude,
+		RadiusMeters: req.RadiusMeters,
+	}
+
+	if err := h.geofenceService.Update(c.Request.Context(), geofence); err != nil {
+		response.Error(c, http.StatusNotFound, "Geofence not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Geofence updated successfully", geofence)
+}
+
+// DeleteGeofence godoc
+// @Summary Delete office geofence
+// @Description Remove an office area (requires geofences:manage)
+// @Tags geofences
+// @Accept json
+// @Produce json
+// @Param id path int true "Geofence ID"
+// @Success 200 {object} response.Response "Geofence deleted successfully"
+// @Failure 400 {object} response.Response "Invalid geofence ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Geofence not found"
+// @Security BearerAuth
+// @Router /geofences/{id} [delete]
+func (h *GeofenceHandler) DeleteGeofence(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid geofence ID")
+		return
+	}
+
+	if err := h.geofenceService.Delete(c.Request.Context(), uint(id)); err != nil {
+		response.Error(c, http.StatusNotFound, "Geofence not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Geofence deleted successfully", nil)
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// authenticate stands in for AuthMiddleware, putting the user and their permissions into the context
+func authenticate(userID uint, permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("permissions", permissions)
+		c.Next()
+	}
+}
+
+func serve(t *testing.T, path, target string, handlers ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	router := gin.New()
+	router.GET(path, handlers...)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"absence/internal/model"
+	"absence/internal/model/request"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HolidayHandler struct {
+	holidayService service.HolidayService
+}
+
+func NewHolidayHandler(holidayService service.HolidayService) *HolidayHandler {
+	return &HolidayHandler{
+		holidayService: holidayService,
+	}
+}
+
+// GetHolidays godoc
+// @Summary Get holidays
+// @Description Get the holiday calendar for a year
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param year query string false "Year (YYYY), defaults to the current year"
+// @Success 200 {object} response.Response{data=[]model.Holiday} "Holidays retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid year format"
+// @Security BearerAuth
+// @Router /holidays [get]
+func (h *HolidayHandler) GetHolidays(c *gin.Context) {
+	year := time.Now().Year()
+	if value := c.Query("year"); value != "" {
+		parsed, err := time.Parse("2006", value)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid year format")
+			return
+		}
+		year = parsed.Year()
+	}
+
+	holidays, err := h.holidayService.GetByYear(c.Request.Context(), year)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Holidays retrieved successfully", holidays)
+}
+
+// GetHoliday godoc
+// @Summary Get holiday by ID
+// @Description Get holiday details by ID
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param id path int true "Holiday ID"
+// @Success 200 {object} response.Response{data=model.Holiday} "Holiday retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid holiday ID"
+// @Failure 404 {object} response.Response "Holiday not found"
+// @Security BearerAuth
+// @Router /holidays/{id} [get]
+func (h *HolidayHandler) GetHoliday(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid holiday ID")
+		return
+	}
+
+	holiday, err := h.holidayService.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Holiday not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Holiday retrieved successfully", holiday)
+}
+
+// CreateHoliday godoc
+// @Summary Create holiday
+// @Description Add a holiday to the calendar (requires holidays:manage)
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param holiday body request.HolidayRequest true "Holiday details"
+// @Success 201 {object} response.Response{data=model.Holiday} "Holiday created successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 409 {object} response.Response "Holiday already exists on this date"
+// @Security BearerAuth
+// @Router /holidays [post]
+func (h *HolidayHandler) CreateHoliday(c *gin.Context) {
+	var req request.HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	holiday, err := toHoliday(req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid date format")
+		return
+	}
+
+	if err := h.holidayService.Create(c.Request.Context(), holiday); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Holiday created successfully", holiday)
+}
+
+// ImportHolidays godoc
+// @Summary Import a year of holidays
+// @Description Bulk import the holidays of one year, optionally replacing the existing ones (requires holidays:manage)
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param holidays body request.ImportHolidaysRequest true "Holidays of the year"
+// @Success 201 {object} response.Response{data=[]model.Holiday} "Holidays imported successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 409 {object} response.Response "Holiday already exists on a date"
+// @Security BearerAuth
+// @Router /holidays/import [post]
+func (h *HolidayHandler) ImportHolidays(c *gin.Context) {
+	var req request.ImportHolidaysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	holidays := make([]model.Holiday, 0, len(req.Holidays))
+	for _, item := range req.Holidays {
+		holiday, err := toHoliday(item)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid date format: "+item.Date)
+			return
+		}
+		holidays = append(holidays, *holiday)
+	}
+
+	if err := h.holidayService.ImportYear(c.Request.Context(), req.Year, holidays, req.Replace); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	imported, err := h.holidayService.GetByYear(c.Request.Context(), req.Year)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Holidays imported successfully", imported)
+}
+
+// UpdateHoliday godoc
+// @Summary Update holiday
+// @Description Update holiday details (requires holidays:manage)
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param id path int true "Holiday ID"
+// @Param holiday body request.HolidayRequest true "Holiday details"
+// @Success 200 {object} response.Response{data=model.Holiday} "Holiday updated successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Holiday not found"
+// @Failure 409 {object} response.Response "Holiday already exists on this date"
+// @Security BearerAuth
+// @Router /holidays/{id} [put]
+func (h *HolidayHandler) UpdateHoliday(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid holiday ID")
+		return
+	}
+
+	var req request.HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	holiday, err := toHoliday(req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid date format")
+		return
+	}
+	holiday.ID = uint(id)
+
+	if err := h.holidayService.Update(c.Request.Context(), holiday); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Holiday updated successfully", holiday)
+}
+
+// DeleteHoliday godoc
+// @Summary Delete holiday
+// @Description Remove a holiday from the calendar (requires holidays:manage)
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param id path int true "Holiday ID"
+// @Success 200 {object} response.Response "Holiday deleted successfully"
+// @Failure 400 {object} response.Response "Invalid holiday ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Holiday not found"
+// @Security BearerAuth
+// @Router /holidays/{id} [delete]
+func (h *HolidayHandler) DeleteHoliday(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid holiday ID")
+		return
+	}
+
+	if err := h.holidayService.Delete(c.Request.Context(), uint(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Holiday deleted successfully", nil)
+}
+
+func (h *HolidayHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrHolidayNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrHolidayExists):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrHolidayOutOfYear), errors.Is(err, service.ErrDuplicateHoliday):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func toHoliday(req request.HolidayRequest) (*model.Holiday, error) {
+	date, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Holiday{
+		Name:        req.Name,
+		Date:        date,
+		Description: req.Description,
+	}, nil
+}
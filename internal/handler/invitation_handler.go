@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"absence/internal/middleware"
+	"absence/internal/model"
+	"absence/internal/model/request"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvitationHandler struct {
+	invitationService service.InvitationService
+}
+
+func NewInvitationHandler(invitationService service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+	}
+}
+
+// CreateInvitation godoc
+// @Summary Invite a user
+// @Description Email an invitation to create an account with the given role and department (requires users:manage; roles other than employee also need roles:manage)
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Param invitation body request.CreateInvitationRequest true "Invitation details"
+// @Success 201 {object} response.Response{data=model.Invitation} "Invitation sent successfully"
+// @Failure 400 {object} response.Response "Invalid input, unknown role or department"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 409 {object} response.Response "Email already registered"
+// @Security BearerAuth
+// @Router /invitations [post]
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var req request.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Inviting someone into a role is as powerful as assigning it
+	if req.Role != model.RoleEmployee && !middleware.HasPermission(c, model.PermissionRolesManage) {
+		response.Error(c, http.StatusForbidden, "You are not allowed to invite users with this role")
+		return
+	}
+
+	inviterID, _ := c.Get("user_id")
+	invitation := &model.Invitation{
+		Email:        req.Email,
+		Role:         req.Role,
+		DepartmentID: req.DepartmentID,
+		InvitedBy:    inviterID.(uint),
+	}
+
+	if err := h.invitationService.Create(c.Request.Context(), invitation); err != nil {
+		switch {
+		case errors.Is(err, service.ErrRoleNotFound), errors.Is(err, service.ErrDepartmentNotFound):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrEmailTaken):
+			response.Error(c, http.StatusConflict, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Invitation sent successfully", invitation)
+}
+
+// GetInvitations godoc
+// @Summary Get invitations
+// @Description Get all invitations with their status, newest first (requires users:manage)
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]model.Invitation} "Invitations retrieved successfully"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Security BearerAuth
+// @Router /invitations [get]
+func (h *InvitationHandler) GetInvitations(c *gin.Context) {
+	invitations, err := h.invitationService.GetAll(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Invitations retrieved successfully", invitations)
+}
+
+// RevokeInvitation godoc
+// @Summary Revoke invitation
+// @Description Withdraw an invitation that has not been accepted yet (requires users:manage)
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} response.Response "Invitation revoked successfully"
+// @Failure 400 {object} response.Response "Invalid invitation ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Invitation not found"
+// @Failure 409 {object} response.Response "Invitation already accepted or revoked"
+// @Security BearerAuth
+// @Router /invitations/{id} [delete]
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid invitation ID")
+		return
+	}
+
+	if err := h.invitationService.Revoke(c.Request.Context(), uint(id)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvitationNotFound):
+			response.Error(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrInvitationNotPending):
+			response.Error(c, http.StatusConflict, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Invitation revoked successfully", nil)
+}
+
+// AcceptInvitation godoc
+// @Summary Accept invitation
+// @Description Create your account from an invitation by choosing a username and password
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Param request body request.AcceptInvitationRequest true "Invitation token and account details"
+// @Success 201 {object} response.Response{data=model.User} "Account created successfully"
+// @Failure 400 {object} response.Response "Invalid input or invalid/expired invitation"
+// @Failure 409 {object} response.Response "Username or email already registered"
+// @Router /invitations/accept [post]
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var req request.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.invitationService.Accept(c.Request.Context(), req.Token, req.Username, req.FullName, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInvitation):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrUsernameTaken), errors.Is(err, service.ErrEmailTaken):
+			response.Error(c, http.StatusConflict, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Account created successfully", user)
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"absence/pkg/jwt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	jwtManager *jwt.JWTManager
+}
+
+func NewJWKSHandler(jwtManager *jwt.JWTManager) *JWKSHandler {
+	return &JWKSHandler{
+		jwtManager: jwtManager,
+	}
+}
+
+// GetJWKS godoc
+// @Summary Get token verification keys
+// @Description Get the public keys (JSON Web Key Set) used to verify access tokens; HS256 secrets are never published
+// @Tags auth
+// @Produce json
+// @Success 200 {object} jwt.JWKS "JSON Web Key Set"
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	// Served in the standard JWKS format rather than the API envelope so any JWT library can consume it
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtManager.JWKS())
+}
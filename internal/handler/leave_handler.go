@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"absence/internal/model"
+	"absence/internal/model/request"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaveHandler struct {
+	leaveService service.LeaveService
+}
+
+func NewLeaveHandler(leaveService service.LeaveService) *LeaveHandler {
+	return &LeaveHandler{
+		leaveService: leaveService,
+	}
+}
+
+// CreateLeaveType godoc
+// @Summary Create leave type
+// @Description Create a new leave type (requires leave_types:manage)
+// @Tags leaves
+// @Accept json
+// @Produce json
+// @Param request body request.CreateLeaveTypeRequest true "Leave type details"
+// @Success 201 {object} response.Response{data=model.LeaveType} "Leave type created successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 500 {object} response.Response "Server error"
+// @Security BearerAuth
+// @Router /leaves/types [post]
+func (h *LeaveHandler) CreateLeaveType(c *gin.Context) {
+	var req request.CreateLeaveTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	leaveType := &model.LeaveType{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := h.leaveService.CreateLeaveType(c.Request.Context(), leaveType); err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Leave type created successfully", leaveType)
+}
+
+// GetLeaveTypes godoc
+// @Summary Get leave types
+// @Description Get all available leave types
+// @Tags leaves
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]model.LeaveType} "Leave types retrieved successfully"
+// @Failure 500 {object} response.Response "Server error"
+// @Security BearerAuth
+// @Router /leaves/types [get]
+func (h *LeaveHandler) GetLeaveTypes(c *gin.Context) {
+	leaveTypes, err := h.leaveService.GetLeaveTypes(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Leave types retrieved successfully", leaveTypes)
+}
+
+// SubmitLeave godoc
+// @Summary Submit leave request
+// @Description Submit a leave request for the authenticated user
+// @Tags leaves
+// @Accept json
+// @Produce json
+// @Param request body request.SubmitLeaveRequest true "Leave request details"
+// @Success 201 {object} response.Response{data=model.LeaveRequest} "Leave request submitted successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 409 {object} response.Response "Overlapping leave request"
+// @Security BearerAuth
+// @Router /leaves [post]
+func (h *LeaveHandler) SubmitLeave(c *gin.Context) {
+	var req request.SubmitLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	startDate, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid start date format")
+		return
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid end date format")
+		return
+	}
+
+	leave := &model.LeaveRequest{
+		UserID:      userID.(uint),
+		LeaveTypeID: req.LeaveTypeID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Reason:      req.Reason,
+	}
+
+	if err := h.leaveService.Submit(c.Request.Context(), leave); err != nil {
+		switch {
+		case errors.Is(err, service.ErrLeaveOverlap):
+			response.Error(c, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrInvalidLeavePeriod), errors.Is(err, service.ErrLeaveTypeNotFound):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Leave request submitted successfully", leave)
+}
+
+// GetLeaves godoc
+// @Summary Get leave requests
+// @Description Users with leaves:read get all leave requests and supervisors those of their department, optionally filtered by status; everyone else gets their own
+// @Tags leaves
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (pending, approved, rejected, cancelled)"
+// @Success 200 {object} response.Response{data=[]model.LeaveRequest} "Leave requests retrieved successfully"
+// @Failure 500 {object} response.Response "Server error"
+// @Security BearerAuth
+// @Router /leaves [get]
+func (h *LeaveHandler) GetLeaves(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	scope := service.ResolveApprovalScope(c.GetStringSlice("permissions"), model.PermissionLeavesRead, model.PermissionLeavesReadDepartment)
+	leaves, err := h.leaveService.GetVisibleByStatus(c.Request.Context(), userID.(uint), scope, c.Query("status"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Leave requests retrieved successfully", leaves)
+}
+
+// GetLeave godoc
+// @Summary Get leave request by ID
+// @Description Get leave request details by ID
+// @Tags leaves
+// @Accept json
+// @Produce json
+// @Param id path int true "Leave request ID"
+// @Success 200 {object} response.Response{data=model.LeaveRequest} "Leave request retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid leave request ID"
+// @Failure 404 {object} response.Response "Leave request not found"
+// @Security BearerAuth
+// @Router /leaves/{id} [get]
+func (h *LeaveHandler) GetLeave(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	// Leave requests outside the viewer's scope are reported as missing
+	scope := service.ResolveApprovalScope(c.GetStringSlice("permissions"), model.PermissionLeavesRead, model.PermissionLeavesReadDepartment)
+	leave, err := h.leaveService.GetVisible(c.Request.Context(), uint(id), userID.(uint), scope)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Leave request not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Leave request retrieved successfully", leave)
+}
+
+// GetUserLeaves godoc
+// @Summary Get user leave requests
+// @Description Get leave request history for a user
+// @Tags leaves
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} response.Response{data=[]model.LeaveRequest} "User leave requests retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 500 {object} response.Response "Server error"
+// @Security BearerAuth
+// @Router /users/{id}/leaves [get]
+func (h *LeaveHandler) GetUserLeaves(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	leaves, err := h.leaveService.GetUserLeaves(c.Request.Context(), uint(userID))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User leave requests retrieved successfully", leaves)
+}
+
+// ApproveLeave godoc
+// @Summary Approve leave request
+// @Description Approve a pending leave request (leaves:approve, or leaves:approve:department for the approver's own department)
+// @Tags leaves
+// @Accept json
+// @Produce json
+// @Param id path int true "Leave request ID"
+// @Success 200 {object} response.Response{data=model.LeaveRequest} "Leave request approved"
+// @Failure 400 {object} response.Response "Invalid leave request ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Leave request not found"
+// @Failure 409 {object} response.Response "Leave request is no longer pending"
+// @Security BearerAuth
+// @Router /leaves/{id}/approve [post]
+func (h *LeaveHandler) ApproveLeave(c *gin.Context) {
+	h.decide(c, h.leaveService.Approve, "Leave request approved")
+}
+
+// RejectLeave godoc
+// @Summary Reject leave request
+// @Description Reject a pending leave request (leaves:approve, or leaves:approve:department for the approver's own department)
+// @Tags leaves
+// @Accept json
+// @Produce json
+// @Param id path int true "Leave request ID"
+// @Success 200 {object} response.Response{data=model.LeaveRequest} "Leave request rejected"
+// @Failure 400 {object} response.Response "Invalid leave request ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Leave request not found"
+// @Failure 409 {object} response.Response "Leave request is no longer pending"
+// @Security BearerAuth
+// @Router /leaves/{id}/reject [post]
+func (h *LeaveHandler) RejectLeave(c *gin.Context) {
+	h.decide(c, h.leaveService.Reject, "Leave request rejected")
+}
+
+func (h *LeaveHandler) decide(c *gin.Context, decide func(ctx context.Context, id, approverID uint, scope service.ApprovalScope) (*model.LeaveRequest, error), message string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+
+	approverID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	scope := service.ResolveApprovalScope(c.GetStringSlice("permissions"), model.PermissionLeavesApprove, model.PermissionLeavesApproveDepartment)
+	leave, err := decide(c.Request.Context(), uint(id), approverID.(uint), scope)
+	if err != nil {
+		h.handleTransitionError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, message, leave)
+}
+
+// CancelLeave godoc
+// @Summary Cancel leave request
+// @Description Cancel one of your own pending leave requests, or an approved one that has not started yet
+// @Tags leaves
+// @Accept json
+// @Produce json
+// @Param id path int true "Leave request ID"
+// @Success 200 {object} response.Response{data=model.LeaveRequest} "Leave request cancelled"
+// @Failure 400 {object} response.Response "Invalid leave request ID"
+// @Failure 404 {object} response.Response "Leave request not found"
+// @Failure 409 {object} response.Response "Leave request can no longer be cancelled"
+// @Security BearerAuth
+// @Router /leaves/{id}/cancel [post]
+func (h *LeaveHandler) CancelLeave(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	leave, err := h.leaveService.Cancel(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		h.handleTransitionError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Leave request cancelled", leave)
+}
+
+func (h *LeaveHandler) handleTransitionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrLeaveNotFound), errors.Is(err, service.ErrLeaveForbidden):
+		response.Error(c, http.StatusNotFound, "Leave request not found")
+	case errors.Is(err, service.ErrLeaveSelfApproval), errors.Is(err, service.ErrLeaveOutOfScope):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrLeaveNotPending), errors.Is(err, service.ErrLeaveNotCancellable):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
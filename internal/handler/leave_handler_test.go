@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+	"absence/internal/service"
+
+	"gorm.io/gorm"
+)
+
+// leaveRepositoryStub and userRepositoryStub back the real leave service with fixed data; other
+// methods are not expected to be called
+type leaveRepositoryStub struct {
+	repository.LeaveRepository
+	leaves map[uint]model.LeaveRequest
+}
+
+func (r *leaveRepositoryStub) GetByID(_ context.Context, id uint) (*model.LeaveRequest, error) {
+	leave, ok := r.leaves[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &leave, nil
+}
+
+type userRepositoryStub struct {
+	repository.UserRepository
+	users map[uint]model.User
+}
+
+func (r *userRepositoryStub) GetByID(_ context.Context, id uint) (*model.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+func TestGetLeaveOwnership(t *testing.T) {
+	sales, support := uint(1), uint(2)
+	leaveService := service.NewLeaveService(
+		&leaveRepositoryStub{leaves: map[uint]model.LeaveRequest{
+			10: {ID: 10, UserID: 1},
+			20: {ID: 20, UserID: 2},
+			30: {ID: 30, UserID: 3},
+		}},
+		&userRepositoryStub{users: map[uint]model.User{
+			1: {ID: 1, DepartmentID: &sales},
+			2: {ID: 2, DepartmentID: &sales},
+			3: {ID: 3, DepartmentID: &support},
+		}},
+	)
+	handler := NewLeaveHandler(leaveService)
+
+	tests := []struct {
+		name        string
+		target      string
+		permissions []string
+		want        int
+	}{
+		{"own request", "/leaves/10", nil, http.StatusOK},
+		{"another user's request", "/leaves/20", nil, http.StatusNotFound},
+		{"another user's request with unrelated permission", "/leaves/20", []string{model.PermissionAttendanceRead}, http.StatusNotFound},
+		{"request from the same department", "/leaves/20", []string{model.PermissionLeavesReadDepartment}, http.StatusOK},
+		{"request from another department", "/leaves/30", []string{model.PermissionLeavesReadDepartment}, http.StatusNotFound},
+		{"request from another department with leaves:read", "/leaves/30", []string{model.PermissionLeavesRead}, http.StatusOK},
+		{"request from another department with all permissions", "/leaves/30", []string{model.PermissionAll}, http.StatusOK},
+		{"missing request", "/leaves/40", []string{model.PermissionAll}, http.StatusNotFound},
+		{"non-numeric id", "/leaves/abc", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := serve(t, "/leaves/:id", tt.target, authenticate(1, tt.permissions...), handler.GetLeave)
+			if got.Code != tt.want {
+				t.Errorf("status = %d, want %d", got.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"absence/internal/model"
+	"absence/internal/model/request"
+	"absence/internal/repository"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLoginAttemptLimit = 100
+	maxLoginAttemptLimit     = 1000
+)
+
+type LoginAttemptHandler struct {
+	loginThrottleService service.LoginThrottleService
+}
+
+func NewLoginAttemptHandler(loginThrottleService service.LoginThrottleService) *LoginAttemptHandler {
+	return &LoginAttemptHandler{
+		loginThrottleService: loginThrottleService,
+	}
+}
+
+// GetLoginAttempts godoc
+// @Summary Get login attempts
+// @Description Review recent login attempts, newest first (requires users:manage)
+// @Tags security
+// @Accept json
+// @Produce json
+// @Param username query string false "Only attempts for this username"
+// @Param ip query string false "Only attempts from this IP address"
+// @Param outcome query string false "success, failure or blocked"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param limit query int false "Maximum number of attempts (default 100, max 1000)"
+// @Success 200 {object} response.Response{data=[]model.LoginAttempt} "Login attempts retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Security BearerAuth
+// @Router /login-attempts [get]
+func (h *LoginAttemptHandler) GetLoginAttempts(c *gin.Context) {
+	filter := repository.LoginAttemptFilter{
+		Username: c.Query("username"),
+		IP:       c.Query("ip"),
+		Outcome:  c.Query("outcome"),
+		Limit:    defaultLoginAttemptLimit,
+	}
+
+	switch filter.Outcome {
+	case "", model.LoginOutcomeSuccess, model.LoginOutcomeFailure, model.LoginOutcomeBlocked:
+	default:
+		response.Error(c, http.StatusBadRequest, "Invalid outcome")
+		return
+	}
+
+	if value := c.Query("from"); value != "" {
+		from, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid from date format")
+			return
+		}
+		filter.From = from
+	}
+	if value := c.Query("to"); value != "" {
+		to, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid to date format")
+			return
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLoginAttemptLimit {
+			response.Error(c, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	attempts, err := h.loginThrottleService.GetAttempts(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Login attempts retrieved successfully", attempts)
+}
+
+// UnlockLogin godoc
+// @Summary Unlock login
+// @Description Clear the failed attempts of a username and/or IP address so they can log in again immediately (requires users:manage)
+// @Tags security
+// @Accept json
+// @Produce json
+// @Param request body request.UnlockLoginRequest true "Username and/or IP address to unlock"
+// @Success 200 {object} response.Response "Login unlocked successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Security BearerAuth
+// @Router /login-attempts/unlock [post]
+func (h *LoginAttemptHandler) UnlockLogin(c *gin.Context) {
+	var req request.UnlockLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.loginThrottleService.Unlock(c.Request.Context(), req.Username, req.IP); err != nil {
+		if errors.Is(err, service.ErrUnlockTargetMissing) {
+			response.Error(c, http.StatusBadRequest, err.Error())
+		} else {
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Login unlocked successfully", nil)
+}
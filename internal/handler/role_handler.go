@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"absence/internal/model"
+	"absence/internal/model/request"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+	roleService service.RoleService
+}
+
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+// GetRoles godoc
+// @Summary Get roles
+// @Description Get all roles with their permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]model.Role} "Roles retrieved successfully"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Security BearerAuth
+// @Router /roles [get]
+func (h *RoleHandler) GetRoles(c *gin.Context) {
+	roles, err := h.roleService.GetAll(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Roles retrieved successfully", roles)
+}
+
+// GetPermissions godoc
+// @Summary Get permissions
+// @Description Get every permission that can be granted to a role
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]string} "Permissions retrieved successfully"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Security BearerAuth
+// @Router /roles/permissions [get]
+func (h *RoleHandler) GetPermissions(c *gin.Context) {
+	response.Success(c, http.StatusOK, "Permissions retrieved successfully", model.Permissions)
+}
+
+// CreateRole godoc
+// @Summary Create role
+// @Description Create a role with a set of permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param role body request.CreateRoleRequest true "Role details"
+// @Success 201 {object} response.Response{data=model.Role} "Role created successfully"
+// @Failure 400 {object} response.Response "Invalid input or unknown permission"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 409 {object} response.Response "Role already exists"
+// @Security BearerAuth
+// @Router /roles [post]
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req request.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	role := &model.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+
+	if err := h.roleService.Create(c.Request.Context(), role); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Role created successfully", role)
+}
+
+// UpdateRole godoc
+// @Summary Update role
+// @Description Replace the description and permissions of a role; the admin role cannot be changed
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path int true "Role ID"
+// @Param role body request.UpdateRoleRequest true "Role details"
+// @Success 200 {object} response.Response{data=model.Role} "Role updated successfully"
+// @Failure 400 {object} response.Response "Invalid input or unknown permission"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Role not found"
+// @Security BearerAuth
+// @Router /roles/{id} [put]
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid role ID")
+		return
+	}
+
+	var req request.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	role := &model.Role{
+		ID:          uint(id),
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+
+	if err := h.roleService.Update(c.Request.Context(), role); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Role updated successfully", role)
+}
+
+// DeleteRole godoc
+// @Summary Delete role
+// @Description Delete a role that is no longer assigned to any user; built-in admin and employee roles cannot be deleted
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path int true "Role ID"
+// @Success 200 {object} response.Response "Role deleted successfully"
+// @Failure 400 {object} response.Response "Invalid role ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Role not found"
+// @Failure 409 {object} response.Response "Role is still assigned to users"
+// @Security BearerAuth
+// @Router /roles/{id} [delete]
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid role ID")
+		return
+	}
+
+	if err := h.roleService.Delete(c.Request.Context(), uint(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Role deleted successfully", nil)
+}
+
+// AssignRole godoc
+// @Summary Assign role to user
+// @Description Give a user a role; the new permissions apply from the user's next login
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param role body request.AssignRoleRequest true "Role name"
+// @Success 200 {object} response.Response{data=model.User} "Role assigned successfully"
+// @Failure 400 {object} response.Response "Invalid input or unknown role"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "User not found"
+// @Security BearerAuth
+// @Router /users/{id}/role [put]
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req request.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.roleService.AssignRole(c.Request.Context(), uint(userID), req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRoleNotFound):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrUserNotFound):
+			response.Error(c, http.StatusNotFound, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Role assigned successfully", user)
+}
+
+func (h *RoleHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrRoleExists), errors.Is(err, service.ErrRoleInUse):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrRoleBuiltIn):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrUnknownPermission):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
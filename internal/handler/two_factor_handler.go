@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"absence/internal/model/request"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	userService service.UserService
+	roleService service.RoleService
+}
+
+func NewTwoFactorHandler(userService service.UserService, roleService service.RoleService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		userService: userService,
+		roleService: roleService,
+	}
+}
+
+// GetTwoFactorStatus godoc
+// @Summary Get two-factor status
+// @Description Get whether two-factor authentication is enabled for the current user and how many recovery codes are left
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=service.TwoFactorStatus} "Two-factor status retrieved successfully"
+// @Security BearerAuth
+// @Router /2fa [get]
+func (h *TwoFactorHandler) GetTwoFactorStatus(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	status, err := h.userService.GetTwoFactorStatus(c.Request.Context(), userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Two-factor status retrieved successfully", status)
+}
+
+// SetupTwoFactor godoc
+// @Summary Start two-factor enrollment
+// @Description Generate a TOTP secret and provisioning URI (render it as a QR code for authenticator apps); confirm with /2fa/enable
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=service.TOTPSetup} "Two-factor setup started"
+// @Failure 409 {object} response.Response "Two-factor authentication is already enabled"
+// @Security BearerAuth
+// @Router /2fa/setup [post]
+func (h *TwoFactorHandler) SetupTwoFactor(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	setup, err := h.userService.SetupTOTP(c.Request.Context(), userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Two-factor setup started", setup)
+}
+
+// EnableTwoFactor godoc
+// @Summary Enable two-factor authentication
+// @Description Confirm enrollment with a code from the authenticator app; the response lists recovery codes that are shown only once
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Param request body request.TwoFactorCodeRequest true "Code from the authenticator app"
+// @Success 200 {object} response.Response{data=map[string]interface{}} "Two-factor authentication enabled"
+// @Failure 400 {object} response.Response "Invalid input, invalid code or setup not started"
+// @Failure 409 {object} response.Response "Two-factor authentication is already enabled"
+// @Security BearerAuth
+// @Router /2fa/enable [post]
+func (h *TwoFactorHandler) EnableTwoFactor(c *gin.Context) {
+	var req request.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	codes, err := h.userService.EnableTOTP(c.Request.Context(), userID.(uint), req.Code)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Two-factor authentication enabled", gin.H{"recovery_codes": codes})
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Description Turn two-factor authentication off; requires the password and a TOTP or recovery code
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Param request body request.DisableTwoFactorRequest true "Password and code"
+// @Success 200 {object} response.Response "Two-factor authentication disabled"
+// @Failure 400 {object} response.Response "Invalid input, wrong password, invalid code or not enabled"
+// @Security BearerAuth
+// @Router /2fa/disable [post]
+func (h *TwoFactorHandler) DisableTwoFactor(c *gin.Context) {
+	var req request.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.userService.DisableTOTP(c.Request.Context(), userID.(uint), req.Password, req.Code); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace all recovery codes after confirming with a TOTP or recovery code
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Param request body request.TwoFactorCodeRequest true "TOTP or recovery code"
+// @Success 200 {object} response.Response{data=map[string]interface{}} "Recovery codes regenerated"
+// @Failure 400 {object} response.Response "Invalid input, invalid code or not enabled"
+// @Security BearerAuth
+// @Router /2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req request.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	codes, err := h.userService.RegenerateRecoveryCodes(c.Request.Context(), userID.(uint), req.Code)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Recovery codes regenerated", gin.H{"recovery_codes": codes})
+}
+
+// ResetTwoFactor godoc
+// @Summary Reset a user's two-factor authentication
+// @Description Turn two-factor authentication off for a user who lost their authenticator and recovery codes (requires users:manage)
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} response.Response "Two-factor authentication reset"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "User not found"
+// @Security BearerAuth
+// @Router /users/{id}/2fa [delete]
+func (h *TwoFactorHandler) ResetTwoFactor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !authorizeTarget(c, h.roleService, uint(id)) {
+		return
+	}
+
+	if err := h.userService.ResetTOTP(c.Request.Context(), uint(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Two-factor authentication reset", nil)
+}
+
+func (h *TwoFactorHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		response.Error(c, http.StatusNotFound, "User not found")
+	case errors.Is(err, service.ErrTOTPAlreadyEnabled):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrTOTPNotSetUp), errors.Is(err, service.ErrTOTPNotEnabled),
+		errors.Is(err, service.ErrInvalidTwoFactorCode), errors.Is(err, service.ErrInvalidPassword):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -1,38 +1,56 @@
 package handler
 
 import (
+	"absence/internal/middleware"
 	"absence/internal/model"
 	"absence/internal/model/request"
+	"absence/internal/repository"
 	"absence/internal/service"
 	"absence/pkg/jwt"
 	"absence/pkg/response"
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
 type UserHandler struct {
-	userService service.UserService
-	jwtManager  *jwt.JWTManager
+	userService          service.UserService
+	roleService          service.RoleService
+	tokenService         service.TokenService
+	loginThrottleService service.LoginThrottleService
 }
 
-func NewUserHandler(userService service.UserService, jwtManager *jwt.JWTManager) *UserHandler {
+func NewUserHandler(userService service.UserService, roleService service.RoleService, tokenService service.TokenService, loginThrottleService service.LoginThrottleService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
-		jwtManager:  jwtManager,
+		userService:          userService,
+		roleService:          roleService,
+		tokenService:         tokenService,
+		loginThrottleService: loginThrottleService,
 	}
 }
 
 // Register godoc
 // @Summary Register a new user
-// @Description Register a new user in the system
+// @Description Self-register an employee account; only available when open registration is enabled
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param user body request.RegisterRequest true "User registration details"
 // @Success 201 {object} response.Response{data=model.User} "User registered successfully"
 // @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Registration is closed or the email domain is not allowed"
+// @Failure 409 {object} response.Response "Username or email already registered"
 // @Failure 500 {object} response.Response "Server error"
 // @Router /register [post]
 // @Example
@@ -41,8 +59,7 @@ func NewUserHandler(userService service.UserService, jwtManager *jwt.JWTManager)
 //	  "username": "john_doe",
 //	  "password": "secure123",
 //	  "full_name": "John Doe",
-//	  "email": "john@example.com",
-//	  "role": "employee"
+//	  "email": "john@example.com"
 //	}
 func (h *UserHandler) Register(c *gin.Context) {
 	var req request.RegisterRequest
@@ -56,11 +73,17 @@ func (h *UserHandler) Register(c *gin.Context) {
 		Password: req.Password,
 		FullName: req.FullName,
 		Email:    req.Email,
-		Role:     req.Role,
 	}
 
 	if err := h.userService.Register(c.Request.Context(), user); err != nil {
-		response.Error(c, http.StatusInternalServerError, err.Error())
+		switch {
+		case errors.Is(err, service.ErrRegistrationClosed), errors.Is(err, service.ErrEmailDomainNotAllowed):
+			response.Error(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, service.ErrUsernameTaken), errors.Is(err, service.ErrEmailTaken):
+			response.Error(c, http.StatusConflict, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
@@ -69,14 +92,17 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary Login user
-// @Description Authenticate user and return token
+// @Description Authenticate user and return an access token with a refresh token. Users with two-factor
+// @Description authentication get a challenge instead, to be completed at /login/2fa.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param user body request.LoginRequest true "Login credentials"
-// @Success 200 {object} response.Response{data=map[string]interface{}} "Login successful"
+// @Success 200 {object} response.Response{data=map[string]interface{}} "Login successful or two-factor authentication required"
 // @Failure 400 {object} response.Response "Invalid input"
 // @Failure 401 {object} response.Response "Invalid credentials"
+// @Failure 403 {object} response.Response "Account is deactivated"
+// @Failure 429 {object} response.Response "Too many failed attempts; see the Retry-After header"
 // @Router /login [post]
 func (h *UserHandler) Login(c *gin.Context) {
 	var req request.LoginRequest
@@ -85,28 +111,257 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
+	attempt, ok := h.reserveLogin(c, req.Username)
+	if !ok {
+		return
+	}
+
 	user, err := h.userService.Login(c.Request.Context(), req.Username, req.Password)
 	if err != nil {
-		response.Error(c, http.StatusUnauthorized, "Invalid credentials")
+		// The reserved attempt already counts as a failure
+		switch {
+		case errors.Is(err, service.ErrInvalidCredentials):
+			response.Error(c, http.StatusUnauthorized, "Invalid credentials")
+		case errors.Is(err, service.ErrUserInactive):
+			h.releaseLogin(c, attempt)
+			response.Error(c, http.StatusForbidden, err.Error())
+		default:
+			h.releaseLogin(c, attempt)
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	// The password alone does not count as a successful login, so it cannot reset the failure count
+	// that protects the second step
+	if user.TOTPEnabled {
+		h.releaseLogin(c, attempt)
+		challenge, err := h.userService.CreateLoginChallenge(c.Request.Context(), user)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		response.Success(c, http.StatusOK, "Two-factor authentication required", gin.H{
+			"two_factor_required": true,
+			"challenge":           challenge.Token,
+			"expires_at":          challenge.ExpiresAt,
+		})
 		return
 	}
+	h.succeedLogin(c, attempt)
 
-	token, err := h.jwtManager.GenerateToken(user.ID, user.Username, user.Role)
+	pair, err := h.tokenService.Issue(c.Request.Context(), user)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to generate token")
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Login successful", gin.H{
-		"token": token,
-		"user": gin.H{
-			"id":        user.ID,
-			"username":  user.Username,
-			"full_name": user.FullName,
-			"email":     user.Email,
-			"role":      user.Role,
-		},
-	})
+	response.Success(c, http.StatusOK, "Login successful", tokenResponse(pair, user))
+}
+
+// LoginTwoFactor godoc
+// @Summary Complete two-factor login
+// @Description Exchange a login challenge and a TOTP or recovery code for an access token with a refresh token
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.LoginTwoFactorRequest true "Login challenge and code"
+// @Success 200 {object} response.Response{data=map[string]interface{}} "Login successful"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 401 {object} response.Response "Invalid or expired challenge, or invalid code"
+// @Failure 429 {object} response.Response "Too many failed attempts; see the Retry-After header"
+// @Router /login/2fa [post]
+func (h *UserHandler) LoginTwoFactor(c *gin.Context) {
+	var req request.LoginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.userService.GetLoginChallengeUser(c.Request.Context(), req.Challenge)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidLoginChallenge) {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+		} else {
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	// Wrong codes count towards the same lockout as wrong passwords
+	attempt, ok := h.reserveLogin(c, user.Username)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.CompleteLoginChallenge(c.Request.Context(), req.Challenge, req.Code); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTwoFactorCode):
+			response.Error(c, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, service.ErrInvalidLoginChallenge):
+			h.releaseLogin(c, attempt)
+			response.Error(c, http.StatusUnauthorized, err.Error())
+		default:
+			h.releaseLogin(c, attempt)
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	h.succeedLogin(c, attempt)
+
+	pair, err := h.tokenService.Issue(c.Request.Context(), user)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Login successful", tokenResponse(pair, user))
+}
+
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access and refresh token; each refresh token works only once
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} response.Response{data=map[string]interface{}} "Token refreshed"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 401 {object} response.Response "Invalid, expired or reused refresh token"
+// @Router /token/refresh [post]
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var req request.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pair, user, err := h.tokenService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRefreshToken), errors.Is(err, service.ErrRefreshTokenReused):
+			response.Error(c, http.StatusUnauthorized, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to refresh token")
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Token refreshed", tokenResponse(pair, user))
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the current access token and, when given, its refresh token
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.LogoutRequest false "Refresh token to revoke"
+// @Success 200 {object} response.Response "Logged out"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Security BearerAuth
+// @Router /logout [post]
+func (h *UserHandler) Logout(c *gin.Context) {
+	var req request.LogoutRequest
+	// The body is optional; without it only the access token is revoked
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	claims, exists := c.Get("claims")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.tokenService.Logout(c.Request.Context(), claims.(*jwt.Claims), req.RefreshToken); err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Logged out", nil)
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Description Get one page of users, optionally searched, filtered and sorted (requires users:read)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param search query string false "Part of the full name, email or username"
+// @Param role query string false "Only users with this role"
+// @Param department_id query int false "Only members of this department"
+// @Param status query string false "active, inactive or deleted; deleted users are only listed with status=deleted"
+// @Param sort query string false "id, username, full_name, email, role or created_at; prefix with - for descending" default(id)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Users per page (max 100)" default(20)
+// @Success 200 {object} response.Response{data=[]model.User,meta=response.Pagination} "Users retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid query parameter"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Security BearerAuth
+// @Router /users [get]
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	filter := repository.UserFilter{
+		Search:   strings.TrimSpace(c.Query("search")),
+		Role:     c.Query("role"),
+		Status:   c.Query("status"),
+		Page:     1,
+		PageSize: defaultPageSize,
+	}
+
+	if value := c.Query("department_id"); value != "" {
+		departmentID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid department ID")
+			return
+		}
+		id := uint(departmentID)
+		filter.DepartmentID = &id
+	}
+
+	switch filter.Status {
+	case "", repository.UserStatusActive, repository.UserStatusInactive, repository.UserStatusDeleted:
+	default:
+		response.Error(c, http.StatusBadRequest, "Invalid status")
+		return
+	}
+
+	if value := c.Query("sort"); value != "" {
+		filter.Sort = strings.TrimPrefix(value, "-")
+		filter.Descending = strings.HasPrefix(value, "-")
+		if !slices.Contains(repository.UserSortFields, filter.Sort) {
+			response.Error(c, http.StatusBadRequest, "Invalid sort field")
+			return
+		}
+	}
+
+	if value := c.Query("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			response.Error(c, http.StatusBadRequest, "Invalid page")
+			return
+		}
+		filter.Page = page
+	}
+	if value := c.Query("page_size"); value != "" {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil || pageSize < 1 || pageSize > maxPageSize {
+			response.Error(c, http.StatusBadRequest, "Invalid page size")
+			return
+		}
+		filter.PageSize = pageSize
+	}
+
+	users, total, err := h.userService.List(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, "Users retrieved successfully", users, response.NewPagination(filter.Page, filter.PageSize, total))
 }
 
 // GetUser godoc
@@ -116,6 +371,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
+// @Param include query string false "Set to 'profile' to embed the employee profile"
 // @Success 200 {object} response.Response{data=model.User} "User details retrieved successfully"
 // @Failure 400 {object} response.Response "Invalid user ID"
 // @Failure 404 {object} response.Response "User not found"
@@ -128,7 +384,12 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.GetByID(c.Request.Context(), uint(id))
+	var user *model.User
+	if c.Query("include") == "profile" {
+		user, err = h.userService.GetWithEmployeeDetail(c.Request.Context(), uint(id))
+	} else {
+		user, err = h.userService.GetByID(c.Request.Context(), uint(id))
+	}
 	if err != nil {
 		response.Error(c, http.StatusNotFound, "User not found")
 		return
@@ -139,14 +400,15 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 
 // UpdateUser godoc
 // @Summary Update user
-// @Description Update user details
+// @Description Update user details; changing the role requires the roles:manage permission
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
 // @Param user body request.UpdateUserRequest true "User details"
 // @Success 200 {object} response.Response{data=model.User} "User updated successfully"
-// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 400 {object} response.Response "Invalid input or unknown role"
+// @Failure 403 {object} response.Response "Forbidden"
 // @Failure 404 {object} response.Response "User not found"
 // @Failure 500 {object} response.Response "Server error"
 // @Security BearerAuth
@@ -164,12 +426,42 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	existing, err := h.userService.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	roleChanged := req.Role != "" && req.Role != existing.Role
+	if roleChanged && !middleware.HasPermission(c, model.PermissionRolesManage) {
+		response.Error(c, http.StatusForbidden, "You are not allowed to change roles")
+		return
+	}
+	// Whoever controls the email can reset the password
+	if req.Email != existing.Email && !authorizeTarget(c, h.roleService, existing.ID) {
+		return
+	}
+
 	user := &model.User{
 		ID:       uint(id),
 		Username: req.Username,
 		FullName: req.FullName,
 		Email:    req.Email,
-		Role:     req.Role,
+	}
+
+	// Assign the role first so an unknown role leaves the user untouched
+	if roleChanged {
+		if _, err := h.roleService.AssignRole(c.Request.Context(), user.ID, req.Role); err != nil {
+			switch {
+			case errors.Is(err, service.ErrRoleNotFound):
+				response.Error(c, http.StatusBadRequest, err.Error())
+			case errors.Is(err, service.ErrUserNotFound):
+				response.Error(c, http.StatusNotFound, "User not found")
+			default:
+				response.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
 	}
 
 	if err := h.userService.Update(c.Request.Context(), user); err != nil {
@@ -182,13 +474,14 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 
 // DeleteUser godoc
 // @Summary Delete user
-// @Description Delete user by ID
+// @Description Soft-delete a user by ID (requires users:manage); the user can no longer log in, their attendance and leave history is kept and they can be restored
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
 // @Success 200 {object} response.Response "User deleted successfully"
 // @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 403 {object} response.Response "Forbidden"
 // @Failure 404 {object} response.Response "User not found"
 // @Failure 500 {object} response.Response "Server error"
 // @Security BearerAuth
@@ -200,10 +493,371 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
+	if !authorizeTarget(c, h.roleService, uint(id)) {
+		return
+	}
+
 	if err := h.userService.Delete(c.Request.Context(), uint(id)); err != nil {
-		response.Error(c, http.StatusNotFound, "User not found")
+		if errors.Is(err, service.ErrUserNotFound) {
+			response.Error(c, http.StatusNotFound, "User not found")
+		} else {
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	// Tokens of a deleted user must stop working right away instead of at expiry
+	if err := h.tokenService.RevokeUser(c.Request.Context(), uint(id)); err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	response.Success(c, http.StatusOK, "User deleted successfully", nil)
 }
+
+// RestoreUser godoc
+// @Summary Restore user
+// @Description Bring back a deleted user (requires users:manage)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} response.Response{data=model.User} "User restored successfully"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "User not found"
+// @Failure 409 {object} response.Response "User is not deleted"
+// @Security BearerAuth
+// @Router /users/{id}/restore [post]
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !authorizeTarget(c, h.roleService, uint(id)) {
+		return
+	}
+
+	user, err := h.userService.Restore(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			response.Error(c, http.StatusNotFound, "User not found")
+		case errors.Is(err, service.ErrUserNotDeleted):
+			response.Error(c, http.StatusConflict, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User restored successfully", user)
+}
+
+// ActivateUser godoc
+// @Summary Activate user
+// @Description Allow a deactivated user to log in again (requires users:manage)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} response.Response{data=model.User} "User activated successfully"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "User not found"
+// @Security BearerAuth
+// @Router /users/{id}/activate [post]
+func (h *UserHandler) ActivateUser(c *gin.Context) {
+	h.setActive(c, true, "User activated successfully")
+}
+
+// DeactivateUser godoc
+// @Summary Deactivate user
+// @Description Block a user from logging in and sign them out everywhere, keeping the account and its history (requires users:manage)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} response.Response{data=model.User} "User deactivated successfully"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "User not found"
+// @Security BearerAuth
+// @Router /users/{id}/deactivate [post]
+func (h *UserHandler) DeactivateUser(c *gin.Context) {
+	h.setActive(c, false, "User deactivated successfully")
+}
+
+func (h *UserHandler) setActive(c *gin.Context, active bool, message string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !authorizeTarget(c, h.roleService, uint(id)) {
+		return
+	}
+
+	user, err := h.userService.SetActive(c.Request.Context(), uint(id), active)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			response.Error(c, http.StatusNotFound, "User not found")
+		} else {
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	if !active {
+		if err := h.tokenService.RevokeUser(c.Request.Context(), uint(id)); err != nil {
+			response.Error(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	response.Success(c, http.StatusOK, message, user)
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the authenticated user's password and sign them out everywhere; the current password is required
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} response.Response "Password changed successfully"
+// @Failure 400 {object} response.Response "Invalid input or wrong current password"
+// @Security BearerAuth
+// @Router /password/change [post]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	var req request.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.userService.ChangePassword(c.Request.Context(), userID.(uint), req.OldPassword, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPassword):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrUserNotFound):
+			response.Error(c, http.StatusNotFound, "User not found")
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	// Tokens stolen along with the old password must stop working
+	if err := h.tokenService.RevokeUser(c.Request.Context(), userID.(uint)); err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Password changed successfully", nil)
+}
+
+// ResetUserPassword godoc
+// @Summary Reset a user's password
+// @Description Set a new password for a user and sign them out everywhere (requires users:manage)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body request.ResetPasswordRequest true "New password"
+// @Success 200 {object} response.Response "Password reset successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "User not found"
+// @Security BearerAuth
+// @Router /users/{id}/password [put]
+func (h *UserHandler) ResetUserPassword(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req request.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !authorizeTarget(c, h.roleService, uint(id)) {
+		return
+	}
+
+	if err := h.userService.ResetPassword(c.Request.Context(), uint(id), req.NewPassword); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			response.Error(c, http.StatusNotFound, "User not found")
+		} else {
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	if err := h.tokenService.RevokeUser(c.Request.Context(), uint(id)); err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Password reset successfully", nil)
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Send a single-use password reset link to the account with this email; always succeeds so accounts cannot be probed
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} response.Response "Password reset requested"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Router /password/forgot [post]
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var req request.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Failures only happen for registered emails, so they must not change the answer
+	if err := h.userService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		log.Printf("failed to send password reset for %q: %v", req.Email, err)
+	}
+
+	response.Success(c, http.StatusOK, "If the email is registered, a password reset link has been sent", nil)
+}
+
+// ConfirmPasswordReset godoc
+// @Summary Reset password with a token
+// @Description Choose a new password using a reset token; the token works once and all sessions are signed out
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.ConfirmPasswordResetRequest true "Reset token and new password"
+// @Success 200 {object} response.Response "Password reset successfully"
+// @Failure 400 {object} response.Response "Invalid input or invalid/expired token"
+// @Router /password/reset [post]
+func (h *UserHandler) ConfirmPasswordReset(c *gin.Context) {
+	var req request.ConfirmPasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.userService.ConfirmPasswordReset(c.Request.Context(), req.Token, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidResetToken) {
+			response.Error(c, http.StatusBadRequest, err.Error())
+		} else {
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	if err := h.tokenService.RevokeUser(c.Request.Context(), user.ID); err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Password reset successfully", nil)
+}
+
+// authorizeTarget writes the error response and returns false when the user's role grants privileged
+// permissions the authenticated user does not hold, so managers cannot take over more powerful accounts
+func authorizeTarget(c *gin.Context, roleService service.RoleService, userID uint) bool {
+	err := roleService.CheckPrivileges(c.Request.Context(), c.GetStringSlice("permissions"), userID)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, service.ErrUserNotFound):
+		response.Error(c, http.StatusNotFound, "User not found")
+	case errors.Is(err, service.ErrUserPrivileged):
+		response.Error(c, http.StatusForbidden, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return false
+}
+
+// reserveLogin stores the attempt as a failure until the credentials prove otherwise. It writes the
+// error response and returns false while the username or client IP is throttled.
+func (h *UserHandler) reserveLogin(c *gin.Context, username string) (*model.LoginAttempt, bool) {
+	attempt := newLoginAttempt(c, username, model.LoginOutcomeFailure)
+	err := h.loginThrottleService.Reserve(c.Request.Context(), attempt)
+	if err == nil {
+		return attempt, true
+	}
+
+	var throttled *service.LoginThrottledError
+	if errors.As(err, &throttled) {
+		h.recordLogin(c, username, model.LoginOutcomeBlocked)
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		response.Error(c, http.StatusTooManyRequests, err.Error())
+	} else {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return nil, false
+}
+
+// succeedLogin and releaseLogin settle a reserved attempt; like recording, this must not decide the login itself
+func (h *UserHandler) succeedLogin(c *gin.Context, attempt *model.LoginAttempt) {
+	if err := h.loginThrottleService.Succeed(c.Request.Context(), attempt); err != nil {
+		log.Printf("failed to record login attempt for %q: %v", attempt.Username, err)
+	}
+}
+
+func (h *UserHandler) releaseLogin(c *gin.Context, attempt *model.LoginAttempt) {
+	if err := h.loginThrottleService.Release(c.Request.Context(), attempt); err != nil {
+		log.Printf("failed to release login attempt for %q: %v", attempt.Username, err)
+	}
+}
+
+// recordLogin stores a login attempt; a failure to record must not decide the login itself
+func (h *UserHandler) recordLogin(c *gin.Context, username, outcome string) {
+	attempt := newLoginAttempt(c, username, outcome)
+	if err := h.loginThrottleService.Record(c.Request.Context(), attempt); err != nil {
+		log.Printf("failed to record login attempt for %q: %v", username, err)
+	}
+}
+
+func newLoginAttempt(c *gin.Context, username, outcome string) *model.LoginAttempt {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	return &model.LoginAttempt{
+		Username:  username,
+		IP:        c.ClientIP(),
+		UserAgent: userAgent,
+		Outcome:   outcome,
+	}
+}
+
+func tokenResponse(pair *service.TokenPair, user *model.User) gin.H {
+	return gin.H{
+		"token":         pair.AccessToken,
+		"refresh_token": pair.RefreshToken,
+		"expires_at":    pair.ExpiresAt,
+		"user": gin.H{
+			"id":        user.ID,
+			"username":  user.Username,
+			"full_name": user.FullName,
+			"email":     user.Email,
+			"role":      user.Role,
+		},
+		"permissions": pair.Permissions,
+	}
+}
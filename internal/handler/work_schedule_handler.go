@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"absence/internal/model"
+	"absence/internal/model/request"
+	"absence/internal/service"
+	"absence/pkg/response"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkScheduleHandler struct {
+	scheduleService service.WorkScheduleService
+}
+
+func NewWorkScheduleHandler(scheduleService service.WorkScheduleService) *WorkScheduleHandler {
+	return &WorkScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// GetDepartmentSchedules godoc
+// @Summary Get department work schedule
+// @Description Get the weekly work schedule of a department
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Department ID"
+// @Success 200 {object} response.Response{data=[]model.WorkSchedule} "Work schedule retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid department ID"
+// @Failure 404 {object} response.Response "Department not found"
+// @Security BearerAuth
+// @Router /departments/{id}/schedules [get]
+func (h *WorkScheduleHandler) GetDepartmentSchedules(c *gin.Context) {
+	departmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid department ID")
+		return
+	}
+
+	schedules, err := h.scheduleService.GetDepartmentSchedules(c.Request.Context(), uint(departmentID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work schedule retrieved successfully", schedules)
+}
+
+// SaveSchedule godoc
+// @Summary Set department working hours for a weekday
+// @Description Create or replace a department's working hours for one day of the week (requires departments:manage)
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Department ID"
+// @Param day path int true "Day of week (1 = Monday, 7 = Sunday)"
+// @Param schedule body request.WorkScheduleRequest true "Working hours"
+// @Success 200 {object} response.Response{data=model.WorkSchedule} "Work schedule saved successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Department not found"
+// @Security BearerAuth
+// @Router /departments/{id}/schedules/{day} [put]
+func (h *WorkScheduleHandler) SaveSchedule(c *gin.Context) {
+	departmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid department ID")
+		return
+	}
+
+	day, err := strconv.Atoi(c.Param("day"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid day of week")
+		return
+	}
+
+	var req request.WorkScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schedule := &model.WorkSchedule{
+		DepartmentID: uint(departmentID),
+		DayOfWeek:    day,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		GraceMinutes: req.GraceMinutes,
+	}
+
+	if err := h.scheduleService.SaveSchedule(c.Request.Context(), schedule); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work schedule saved successfully", schedule)
+}
+
+// DeleteSchedule godoc
+// @Summary Remove department working hours for a weekday
+// @Description Remove a department's working hours for one day of the week, making it a day off (requires departments:manage)
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Department ID"
+// @Param day path int true "Day of week (1 = Monday, 7 = Sunday)"
+// @Success 200 {object} response.Response "Work schedule deleted successfully"
+// @Failure 400 {object} response.Response "Invalid input"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Work schedule not found"
+// @Security BearerAuth
+// @Router /departments/{id}/schedules/{day} [delete]
+func (h *WorkScheduleHandler) DeleteSchedule(c *gin.Context) {
+	departmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid department ID")
+		return
+	}
+
+	day, err := strconv.Atoi(c.Param("day"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid day of week")
+		return
+	}
+
+	if err := h.scheduleService.DeleteSchedule(c.Request.Context(), uint(departmentID), day); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work schedule deleted successfully", nil)
+}
+
+func (h *WorkScheduleHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrDepartmentNotFound), errors.Is(err, service.ErrScheduleNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidDayOfWeek),
+		errors.Is(err, service.ErrInvalidClockTime),
+		errors.Is(err, service.ErrEmptyScheduleSpan):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,70 @@
+package job
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"absence/internal/service"
+)
+
+// AbsenceJob marks users who never checked in as absent once the daily cutoff has passed
+type AbsenceJob struct {
+	attendanceService service.AttendanceService
+	cutoff            time.Duration
+}
+
+func NewAbsenceJob(attendanceService service.AttendanceService, config *service.AttendanceConfig) *AbsenceJob {
+	return &AbsenceJob{
+		attendanceService: attendanceService,
+		cutoff:            config.AbsenceCutoff,
+	}
+}
+
+// Run blocks until ctx is cancelled, processing each day at the configured cutoff.
+// If started after today's cutoff it processes today right away, which is safe
+// because days that already have a record are skipped.
+func (j *AbsenceJob) Run(ctx context.Context) {
+	now := time.Now()
+	if !now.Before(j.cutoffOf(now)) {
+		j.process(ctx, now)
+	}
+
+	for {
+		next := j.nextRun(time.Now())
+		log.Printf("Absence job: next run at %s", next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			j.process(ctx, next)
+		}
+	}
+}
+
+func (j *AbsenceJob) process(ctx context.Context, day time.Time) {
+	marked, err := j.attendanceService.MarkMissingDays(ctx, day)
+	if err != nil {
+		log.Printf("Absence job: failed for %s after marking %d record(s): %v", day.Format("2006-01-02"), marked, err)
+		return
+	}
+	log.Printf("Absence job: marked %d record(s) for %s", marked, day.Format("2006-01-02"))
+}
+
+// cutoffOf returns the cutoff moment of the calendar day of t
+func (j *AbsenceJob) cutoffOf(t time.Time) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return midnight.Add(j.cutoff)
+}
+
+// nextRun returns the first cutoff strictly after t
+func (j *AbsenceJob) nextRun(t time.Time) time.Time {
+	next := j.cutoffOf(t)
+	if !next.After(t) {
+		next = j.cutoffOf(t.AddDate(0, 0, 1))
+	}
+	return next
+}
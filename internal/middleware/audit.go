@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"absence/pkg/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditContext records the client IP for the audit log on every request, including public ones such as
+// registration. AuthMiddleware adds the authenticated user on protected routes.
+func AuditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), audit.Actor{IP: c.ClientIP()}))
+		c.Next()
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"strings"
 
+	"absence/internal/service"
+	"absence/pkg/audit"
 	"absence/pkg/jwt"
 	"absence/pkg/response"
 
@@ -11,12 +13,14 @@ import (
 )
 
 type AuthMiddleware struct {
-	jwtManager *jwt.JWTManager
+	jwtManager   *jwt.JWTManager
+	tokenService service.TokenService
 }
 
-func NewAuthMiddleware(jwtManager *jwt.JWTManager) *AuthMiddleware {
+func NewAuthMiddleware(jwtManager *jwt.JWTManager, tokenService service.TokenService) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtManager: jwtManager,
+		jwtManager:   jwtManager,
+		tokenService: tokenService,
 	}
 }
 
@@ -47,10 +51,32 @@ func (m *AuthMiddleware) AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		revoked, err := m.tokenService.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "Failed to verify token")
+			c.Abort()
+			return
+		}
+		if revoked {
+			response.Error(c, http.StatusUnauthorized, "Token has been revoked")
+			c.Abort()
+			return
+		}
+
 		// Set user information in context
+		c.Set("claims", claims)
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("permissions", claims.Permissions)
+
+		// Attribute database changes made while handling the request to the user
+		userID := claims.UserID
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), audit.Actor{
+			UserID:   &userID,
+			Username: claims.Username,
+			IP:       c.ClientIP(),
+		}))
 
 		c.Next()
 	}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"absence/internal/model"
+	"absence/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission only lets requests through when the authenticated user holds at least one of
+// the given permissions. It must run after AuthMiddleware, which puts the permissions into the context.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasAnyPermission(c, permissions...) {
+			response.Error(c, http.StatusForbidden, "You are not allowed to access this resource")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSelfOrPermission lets requests through when the user ID in the given path parameter belongs
+// to the authenticated user, or when the user holds at least one of the given permissions.
+func RequireSelfOrPermission(param string, permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if HasAnyPermission(c, permissions...) || isSelf(c, param) {
+			c.Next()
+			return
+		}
+
+		response.Error(c, http.StatusForbidden, "You are not allowed to access this resource")
+		c.Abort()
+	}
+}
+
+// HasPermission reports whether the authenticated user holds the given permission
+func HasPermission(c *gin.Context, permission string) bool {
+	return HasAnyPermission(c, permission)
+}
+
+// HasAnyPermission reports whether the authenticated user holds at least one of the given permissions
+func HasAnyPermission(c *gin.Context, permissions ...string) bool {
+	granted := c.GetStringSlice("permissions")
+	if slices.Contains(granted, model.PermissionAll) {
+		return true
+	}
+	for _, permission := range permissions {
+		if slices.Contains(granted, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSelf(c *gin.Context, param string) bool {
+	id, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil {
+		return false
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return false
+	}
+	current, ok := userID.(uint)
+	return ok && current == uint(id)
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"absence/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// authenticate stands in for AuthMiddleware, putting the user and their permissions into the context
+func authenticate(userID uint, permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("permissions", permissions)
+		c.Next()
+	}
+}
+
+func serve(t *testing.T, path, target string, handlers ...gin.HandlerFunc) int {
+	t.Helper()
+	router := gin.New()
+	handlers = append(handlers, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET(path, handlers...)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder.Code
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		want        int
+	}{
+		{"employee without permissions", nil, http.StatusForbidden},
+		{"other permission", []string{model.PermissionLeavesRead}, http.StatusForbidden},
+		{"required permission", []string{model.PermissionAttendanceRead}, http.StatusOK},
+		{"one of the required permissions", []string{model.PermissionUsersRead}, http.StatusOK},
+		{"all permissions", []string{model.PermissionAll}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := serve(t, "/attendance", "/attendance",
+				authenticate(1, tt.permissions...),
+				RequirePermission(model.PermissionAttendanceRead, model.PermissionUsersRead))
+			if got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireSelfOrPermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		permissions []string
+		want        int
+	}{
+		{"own data", "/users/1/attendance", nil, http.StatusOK},
+		{"another user's data", "/users/2/attendance", nil, http.StatusForbidden},
+		{"non-numeric id", "/users/abc/attendance", nil, http.StatusForbidden},
+		{"id with trailing characters", "/users/1abc/attendance", nil, http.StatusForbidden},
+		{"negative id", "/users/-1/attendance", nil, http.StatusForbidden},
+		{"another user's data with permission", "/users/2/attendance", []string{model.PermissionAttendanceRead}, http.StatusOK},
+		{"another user's data with other permission", "/users/2/attendance", []string{model.PermissionLeavesRead}, http.StatusForbidden},
+		{"another user's data with all permissions", "/users/2/attendance", []string{model.PermissionAll}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := serve(t, "/users/:id/attendance", tt.target,
+				authenticate(1, tt.permissions...),
+				RequireSelfOrPermission("id", model.PermissionAttendanceRead))
+			if got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireSelfOrPermissionWithoutUser(t *testing.T) {
+	got := serve(t, "/users/:id/attendance", "/users/0/attendance",
+		RequireSelfOrPermission("id", model.PermissionAttendanceRead))
+	if got != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", got, http.StatusForbidden)
+	}
+}
@@ -2,19 +2,91 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// Attendance statuses for a user's workday
+const (
+	AttendanceStatusPresent = "present"
+	AttendanceStatusLate    = "late"
+	AttendanceStatusAbsent  = "absent"
+	AttendanceStatusLeave   = "leave"
+)
+
+// Sources a check-in or check-out can be reported from
+const (
+	AttendanceSourceWeb    = "web"
+	AttendanceSourceMobile = "mobile"
+	AttendanceSourceKiosk  = "kiosk"
+	AttendanceSourceSystem = "system"
+	// AttendanceSourceManual marks times entered or edited by an administrator
+	AttendanceSourceManual = "manual"
+)
+
+// Schedule classifications for check-in and check-out
+const (
+	ScheduleUnscheduled = "unscheduled"
+	ScheduleOnTime      = "on_time"
+	ScheduleLate        = "late"
+	ScheduleEarlyLeave  = "early_leave"
 )
 
-// Attendance represents the attendance record in the system
+// Attendance is one check-in/check-out session of a user. A workday can have several sessions, such as
+// a split shift, and a night shift session ends on the day after its workday. Days the system marks
+// as absent or leave have a single record without check-in.
 type Attendance struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	UserID    uint      `json:"user_id" gorm:"not null"`
-	User      User      `json:"user" gorm:"foreignKey:UserID"`
-	CheckIn   time.Time `json:"check_in" gorm:"not null"`
-	CheckOut  time.Time `json:"check_out"`
-	Location  string    `json:"location"`
-	Notes     string    `json:"notes"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID     uint `json:"id" gorm:"primaryKey"`
+	UserID uint `json:"user_id" gorm:"not null;index:idx_attendance_user_date"`
+	User   User `json:"user" gorm:"foreignKey:UserID"`
+	// Date is the workday the record belongs to, which for sessions after midnight of an overnight
+	// schedule is the day before the check-in
+	Date time.Time `json:"date" gorm:"type:date;index:idx_attendance_user_date"`
+	// CheckIn is nil for days the system marked as absent or leave
+	CheckIn *time.Time `json:"check_in"`
+	// CheckOut is nil while the session is open
+	CheckOut          *time.Time `json:"check_out"`
+	Status            string     `json:"status" gorm:"size:20;check:status IN ('present', 'late', 'absent', 'leave')"`
+	LocationIn        string     `json:"location_in"`
+	LatitudeIn        *float64   `json:"latitude_in"`
+	LongitudeIn       *float64   `json:"longitude_in"`
+	GeofenceInID      *uint      `json:"geofence_in_id"`
+	RemoteCheckIn     bool       `json:"remote_check_in" gorm:"not null;default:false"`
+	NotesIn           string     `json:"notes_in" gorm:"type:text"`
+	DeviceIn          string     `json:"device_in" gorm:"size:100"`
+	SourceIn          string     `json:"source_in" gorm:"size:20"`
+	LocationOut       string     `json:"location_out"`
+	LatitudeOut       *float64   `json:"latitude_out"`
+	LongitudeOut      *float64   `json:"longitude_out"`
+	GeofenceOutID     *uint      `json:"geofence_out_id"`
+	RemoteCheckOut    bool       `json:"remote_check_out" gorm:"not null;default:false"`
+	NotesOut          string     `json:"notes_out" gorm:"type:text"`
+	DeviceOut         string     `json:"device_out" gorm:"size:100"`
+	SourceOut         string     `json:"source_out" gorm:"size:20"`
+	CheckInStatus     string     `json:"check_in_status" gorm:"size:20"`
+	CheckOutStatus    string     `json:"check_out_status" gorm:"size:20"`
+	LateMinutes       int        `json:"late_minutes" gorm:"not null;default:0"`
+	EarlyLeaveMinutes int        `json:"early_leave_minutes" gorm:"not null;default:0"`
+	HolidayOvertime   bool       `json:"holiday_overtime" gorm:"not null;default:false"`
+	// Breaks are loaded when reading records, not when listing the sessions of a day
+	Breaks []AttendanceBreak `json:"breaks" gorm:"foreignKey:AttendanceID"`
+	// BreakMinutes adds up the finished breaks within the session
+	BreakMinutes int `json:"break_minutes" gorm:"not null;default:0"`
+	// WorkedMinutes is the time between check-in and check-out minus breaks, 0 until checked out
+	WorkedMinutes int `json:"worked_minutes" gorm:"not null;default:0"`
+	// BreakExceeded and BreakMissing flag sessions breaking the configured break rules
+	BreakExceeded bool `json:"break_exceeded" gorm:"not null;default:false"`
+	BreakMissing  bool `json:"break_missing" gorm:"not null;default:false"`
+	// RegularMinutes and OvertimeMinutes split WorkedMinutes when reading records, since the weekly
+	// overtime threshold depends on the other sessions of the week
+	RegularMinutes  int `json:"regular_minutes" gorm:"-"`
+	OvertimeMinutes int `json:"overtime_minutes" gorm:"-"`
+	// WeightedMinutes counts overtime on weekends and holidays with their multiplier
+	WeightedMinutes int       `json:"weighted_minutes" gorm:"-"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	// DeletedAt hides removed records without losing the attendance history
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // TableName specifies the table name for Attendance
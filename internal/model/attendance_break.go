@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+)
+
+// AttendanceBreak is a break taken during an attendance session. Breaks only count towards the
+// session's break time where they fall between its check-in and check-out.
+type AttendanceBreak struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	AttendanceID uint      `gorm:"not null;index" json:"attendance_id"`
+	StartedAt    time.Time `gorm:"not null" json:"started_at"`
+	// EndedAt is nil while the break is running
+	EndedAt   *time.Time `json:"ended_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Minutes returns how much of the break falls within [from, to)
+func (b AttendanceBreak) Minutes(from, to time.Time) int {
+	if b.EndedAt == nil {
+		return 0
+	}
+	start, end := b.StartedAt, *b.EndedAt
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return int(end.Sub(start) / time.Minute)
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+)
+
+// Attendance correction statuses
+const (
+	CorrectionStatusPending   = "pending"
+	CorrectionStatusApproved  = "approved"
+	CorrectionStatusRejected  = "rejected"
+	CorrectionStatusCancelled = "cancelled"
+)
+
+// AttendanceCorrection is a user's request to fix the check-in and/or check-out time of one of their
+// attendance records, e.g. after forgetting to check out. A nil proposed time keeps the recorded one.
+// Once approved, the replaced times are kept in OriginalCheckIn and OriginalCheckOut.
+type AttendanceCorrection struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	AttendanceID     uint       `gorm:"not null;index" json:"attendance_id"`
+	UserID           uint       `gorm:"not null;index" json:"user_id"`
+	User             User       `gorm:"foreignKey:UserID" json:"user"`
+	CheckIn          *time.Time `json:"check_in"`
+	CheckOut         *time.Time `json:"check_out"`
+	Reason           string     `gorm:"type:text;not null" json:"reason"`
+	Status           string     `gorm:"not null;size:20;index;check:status IN ('pending', 'approved', 'rejected', 'cancelled')" json:"status"`
+	DecidedBy        *uint      `json:"decided_by"`
+	DecidedAt        *time.Time `json:"decided_at"`
+	OriginalCheckIn  *time.Time `json:"original_check_in"`
+	OriginalCheckOut *time.Time `json:"original_check_out"`
+	CreatedAt        time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
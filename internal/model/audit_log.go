@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+)
+
+// Audit log actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditRedacted replaces the values of fields that are never exposed by the API, such as password hashes
+const AuditRedacted = "[redacted]"
+
+// AuditChange is the value of one column before and after a change; Old is nil for creates and New is nil for deletes
+type AuditChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditLog records one created, updated or deleted row together with who changed it and from where.
+// ActorID is nil for changes made by the system, e.g. the absence job or public endpoints.
+type AuditLog struct {
+	ID            uint                   `gorm:"primaryKey" json:"id"`
+	ActorID       *uint                  `gorm:"index" json:"actor_id"`
+	ActorUsername string                 `gorm:"size:50" json:"actor_username"`
+	Action        string                 `gorm:"not null;size:20" json:"action"`
+	Entity        string                 `gorm:"not null;size:64;index:idx_audit_logs_entity" json:"entity"`
+	EntityID      string                 `gorm:"size:64;index:idx_audit_logs_entity" json:"entity_id"`
+	Changes       map[string]AuditChange `gorm:"type:text;serializer:json" json:"changes"`
+	IP            string                 `gorm:"column:ip;size:45" json:"ip"`
+	CreatedAt     time.Time              `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+}
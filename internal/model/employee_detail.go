@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+// EmployeeDetail holds HR profile data for a user. The department itself is
+// tracked on User.DepartmentID so membership has a single source of truth.
+type EmployeeDetail struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"uniqueIndex;not null" json:"user_id"`
+	EmployeeID string    `gorm:"uniqueIndex;not null;size:20" json:"employee_id"`
+	Position   string    `gorm:"not null;size:100" json:"position"`
+	JoinDate   time.Time `gorm:"type:date;not null" json:"join_date"`
+	CreatedAt  time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
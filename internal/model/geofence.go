@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// Geofence is a circular area around an office inside which check-ins count as on-site
+type Geofence struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Name         string    `gorm:"not null;size:100" json:"name"`
+	Latitude     float64   `gorm:"not null" json:"latitude"`
+	Longitude    float64   `gorm:"not null" json:"longitude"`
+	RadiusMeters float64   `gorm:"not null" json:"radius_meters"`
+	CreatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+// Holiday represents a company-wide day off
+type Holiday struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"not null;size:100" json:"name"`
+	Date        time.Time `gorm:"type:date;not null;uniqueIndex" json:"date"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+)
+
+// Invitation status values, derived from the timestamps
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusRevoked  = "revoked"
+	InvitationStatusExpired  = "expired"
+)
+
+// Invitation lets an administrator onboard someone with a predefined email, role and department.
+// The invitee receives a single-use token and chooses their username and password when redeeming it.
+type Invitation struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Email        string     `gorm:"not null;size:100;index" json:"email"`
+	Role         string     `gorm:"not null;size:50" json:"role"`
+	DepartmentID *uint      `json:"department_id"`
+	TokenHash    string     `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	InvitedBy    uint       `gorm:"not null" json:"invited_by"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt   *time.Time `json:"accepted_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	// UserID is the account created when the invitation was accepted
+	UserID    *uint     `json:"user_id"`
+	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+
+	// Status is filled in from StatusAt when the invitation is returned by the API
+	Status string `gorm:"-" json:"status"`
+}
+
+// StatusAt reports where the invitation stands at the given time
+func (i *Invitation) StatusAt(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationStatusAccepted
+	case i.RevokedAt != nil:
+		return InvitationStatusRevoked
+	case !i.ExpiresAt.After(now):
+		return InvitationStatusExpired
+	default:
+		return InvitationStatusPending
+	}
+}
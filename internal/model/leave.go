@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// Leave request statuses
+const (
+	LeaveStatusPending   = "pending"
+	LeaveStatusApproved  = "approved"
+	LeaveStatusRejected  = "rejected"
+	LeaveStatusCancelled = "cancelled"
+)
+
+// LeaveType represents a category of leave such as annual or sick leave
+type LeaveType struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"not null;size:50" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// LeaveRequest represents a user's request for time off
+type LeaveRequest struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	User        User       `gorm:"foreignKey:UserID" json:"user"`
+	LeaveTypeID uint       `gorm:"not null" json:"leave_type_id"`
+	LeaveType   LeaveType  `gorm:"foreignKey:LeaveTypeID" json:"leave_type"`
+	StartDate   time.Time  `gorm:"type:date;not null" json:"start_date"`
+	EndDate     time.Time  `gorm:"type:date;not null" json:"end_date"`
+	Reason      string     `gorm:"type:text" json:"reason"`
+	Status      string     `gorm:"not null;size:20;index;check:status IN ('pending', 'approved', 'rejected', 'cancelled')" json:"status"`
+	ApprovedBy  *uint      `json:"approved_by"`
+	DecidedAt   *time.Time `json:"decided_at"`
+	CreatedAt   time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
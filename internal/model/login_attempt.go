@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// Login attempt outcomes
+const (
+	LoginOutcomeSuccess = "success"
+	LoginOutcomeFailure = "failure"
+	// LoginOutcomeBlocked is an attempt turned away by a delay or lockout before checking the password
+	LoginOutcomeBlocked = "blocked"
+)
+
+// LoginAttempt records one login attempt for throttling and security review.
+// Failures are cleared by a successful login or an admin unlock so they stop counting towards a lockout.
+type LoginAttempt struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Username  string     `gorm:"not null;size:50;index" json:"username"`
+	IP        string     `gorm:"column:ip;not null;size:45;index" json:"ip"`
+	UserAgent string     `gorm:"size:255" json:"user_agent"`
+	Outcome   string     `gorm:"not null;size:20" json:"outcome"`
+	ClearedAt *time.Time `json:"cleared_at"`
+	CreatedAt time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+}
@@ -1,19 +1,37 @@
 package request
 
+import (
+	"time"
+)
+
 // CheckInRequest represents the request body for check-in
 type CheckInRequest struct {
 	// Location is optional and can be used to store check-in location
 	Location string `json:"location" example:"Jakarta"`
+	// Latitude and Longitude are the device coordinates, validated against the office geofences
+	Latitude  *float64 `json:"latitude" example:"-6.200000" binding:"omitempty,min=-90,max=90,required_with=Longitude"`
+	Longitude *float64 `json:"longitude" example:"106.816666" binding:"omitempty,min=-180,max=180,required_with=Latitude"`
 	// Notes is optional and can be used to store additional information
 	Notes string `json:"notes" example:"Working from home"`
+	// Device optionally identifies the device used, e.g. a device ID or model
+	Device string `json:"device" example:"Pixel 8" binding:"max=100"`
+	// Source is the client the check-in was made from (web, mobile or kiosk), defaults to web
+	Source string `json:"source" example:"mobile" binding:"omitempty,oneof=web mobile kiosk"`
 }
 
 // CheckOutRequest represents the request body for check-out
 type CheckOutRequest struct {
 	// Location is optional and can be used to store check-out location
 	Location string `json:"location" example:"Jakarta"`
+	// Latitude and Longitude are the device coordinates, validated against the office geofences
+	Latitude  *float64 `json:"latitude" example:"-6.200000" binding:"omitempty,min=-90,max=90,required_with=Longitude"`
+	Longitude *float64 `json:"longitude" example:"106.816666" binding:"omitempty,min=-180,max=180,required_with=Latitude"`
 	// Notes is optional and can be used to store additional information
 	Notes string `json:"notes" example:"Finished work for today"`
+	// Device optionally identifies the device used, e.g. a device ID or model
+	Device string `json:"device" example:"Pixel 8" binding:"max=100"`
+	// Source is the client the check-out was made from (web, mobile or kiosk), defaults to web
+	Source string `json:"source" example:"mobile" binding:"omitempty,oneof=web mobile kiosk"`
 }
 
 // GetAttendanceRequest represents the request parameters for getting attendance
@@ -25,3 +43,47 @@ type GetAttendanceRequest struct {
 	// Year is optional and can be used to filter attendance by year (format: YYYY)
 	Year string `json:"year" example:"2024"`
 }
+
+// SubmitAttendanceCorrectionRequest represents the request body for correcting one of your attendance records
+type SubmitAttendanceCorrectionRequest struct {
+	AttendanceID uint `json:"attendance_id" example:"42" binding:"required"`
+	// CheckIn and CheckOut are the corrected times (RFC 3339); omit the one that is already right
+	CheckIn  *time.Time `json:"check_in" example:"2024-03-20T08:00:00+07:00"`
+	CheckOut *time.Time `json:"check_out" example:"2024-03-20T17:00:00+07:00"`
+	Reason   string     `json:"reason" example:"Forgot to check out" binding:"required"`
+}
+
+// CreateAttendanceRequest represents the request body for entering attendance for any user
+type CreateAttendanceRequest struct {
+	UserID uint `json:"user_id" example:"7" binding:"required"`
+	// Date is the workday (format: YYYY-MM-DD)
+	Date string `json:"date" example:"2024-03-20" binding:"required"`
+	// CheckIn and CheckOut are RFC 3339 times; leave both out for an absent or leave day
+	CheckIn  *time.Time `json:"check_in" example:"2024-03-20T08:00:00+07:00"`
+	CheckOut *time.Time `json:"check_out" example:"2024-03-20T17:00:00+07:00"`
+	// Status is only used without check-in (absent or leave)
+	Status string `json:"status" example:"absent" binding:"omitempty,oneof=absent leave"`
+}
+
+// UpdateAttendanceRequest represents the request body for replacing the times of an attendance record
+type UpdateAttendanceRequest struct {
+	// CheckIn and CheckOut are RFC 3339 times; omitted times are removed from the record
+	CheckIn  *time.Time `json:"check_in" example:"2024-03-20T08:00:00+07:00"`
+	CheckOut *time.Time `json:"check_out" example:"2024-03-20T17:00:00+07:00"`
+	// Status is only used without check-in (absent or leave)
+	Status string `json:"status" example:"leave" binding:"omitempty,oneof=absent leave"`
+}
+
+// BulkAttendanceRequest represents the request body for applying the same times or status to many users' day
+type BulkAttendanceRequest struct {
+	// Date is the workday (format: YYYY-MM-DD)
+	Date string `json:"date" example:"2024-03-20" binding:"required"`
+	// UserIDs and DepartmentID select the users; the active members of the department are added to UserIDs
+	UserIDs      []uint `json:"user_ids" example:"7,8"`
+	DepartmentID *uint  `json:"department_id" example:"2"`
+	// CheckIn and CheckOut are times of day (format: HH:MM); a check-out not after the check-in is on the next day
+	CheckIn  string `json:"check_in" example:"08:00"`
+	CheckOut string `json:"check_out" example:"17:00"`
+	// Status is only used without check-in (absent or leave)
+	Status string `json:"status" example:"absent" binding:"omitempty,oneof=absent leave"`
+}
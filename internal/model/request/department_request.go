@@ -0,0 +1,8 @@
+package request
+
+// DepartmentRequest represents the request body for creating or updating a department
+// @Description Department create/update request
+type DepartmentRequest struct {
+	Name        string `json:"name" example:"Engineering" binding:"required,max=100"`
+	Description string `json:"description" example:"Product engineering team"`
+}
@@ -0,0 +1,12 @@
+package request
+
+// EmployeeDetailRequest represents the request body for creating or updating an employee profile
+// @Description Employee profile request
+type EmployeeDetailRequest struct {
+	EmployeeID string `json:"employee_id" example:"EMP-0001" binding:"required,max=20"`
+	Position   string `json:"position" example:"Software Engineer" binding:"required,max=100"`
+	// JoinDate is the employee's first working day (format: YYYY-MM-DD)
+	JoinDate string `json:"join_date" example:"2023-01-09" binding:"required"`
+	// DepartmentID is optional; when omitted the user's current department is kept
+	DepartmentID *uint `json:"department_id" example:"1"`
+}
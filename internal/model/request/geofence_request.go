@@ -0,0 +1,10 @@
+package request
+
+// GeofenceRequest represents the request body for creating or updating an office geofence
+type GeofenceRequest struct {
+	Name      string   `json:"name" example:"Jakarta HQ" binding:"required,max=100"`
+	Latitude  *float64 `json:"latitude" example:"-6.200000" binding:"required,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" example:"106.816666" binding:"required,min=-180,max=180"`
+	// RadiusMeters is how far from the center point a check-in still counts as inside the office
+	RadiusMeters float64 `json:"radius_meters" example:"150" binding:"required,gt=0"`
+}
@@ -0,0 +1,17 @@
+package request
+
+// HolidayRequest represents the request body for creating or updating a holiday
+type HolidayRequest struct {
+	Name string `json:"name" example:"Independence Day" binding:"required,max=100"`
+	// Date is the day of the holiday (format: YYYY-MM-DD)
+	Date        string `json:"date" example:"2024-08-17" binding:"required"`
+	Description string `json:"description" example:"National holiday"`
+}
+
+// ImportHolidaysRequest represents the request body for importing a full year of holidays
+type ImportHolidaysRequest struct {
+	Year int `json:"year" example:"2024" binding:"required,min=1970,max=9999"`
+	// Replace removes every existing holiday in the year before importing
+	Replace  bool             `json:"replace" example:"false"`
+	Holidays []HolidayRequest `json:"holidays" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,17 @@
+package request
+
+// CreateLeaveTypeRequest represents the request body for creating a leave type
+type CreateLeaveTypeRequest struct {
+	Name        string `json:"name" example:"Annual Leave" binding:"required,max=50"`
+	Description string `json:"description" example:"Paid annual leave"`
+}
+
+// SubmitLeaveRequest represents the request body for submitting a leave request
+type SubmitLeaveRequest struct {
+	LeaveTypeID uint `json:"leave_type_id" example:"1" binding:"required"`
+	// StartDate is the first day of leave (format: YYYY-MM-DD)
+	StartDate string `json:"start_date" example:"2024-03-20" binding:"required"`
+	// EndDate is the last day of leave (format: YYYY-MM-DD)
+	EndDate string `json:"end_date" example:"2024-03-22" binding:"required"`
+	Reason  string `json:"reason" example:"Family vacation"`
+}
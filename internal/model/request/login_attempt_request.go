@@ -0,0 +1,8 @@
+package request
+
+// UnlockLoginRequest represents the request to lift a login lockout
+// @Description Login unlock request; at least one of username and ip is required
+type UnlockLoginRequest struct {
+	Username string `json:"username" example:"johndoe"`
+	IP       string `json:"ip" example:"203.0.113.7"`
+}
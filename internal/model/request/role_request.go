@@ -0,0 +1,22 @@
+package request
+
+// CreateRoleRequest represents the request to create a role
+// @Description Role creation request
+type CreateRoleRequest struct {
+	Name        string   `json:"name" example:"payroll" binding:"required,max=50"`
+	Description string   `json:"description" example:"Reads attendance for payroll runs" binding:"max=255"`
+	Permissions []string `json:"permissions" example:"attendance:read"`
+}
+
+// UpdateRoleRequest represents the request to update a role
+// @Description Role update request
+type UpdateRoleRequest struct {
+	Description string   `json:"description" example:"Reads attendance for payroll runs" binding:"max=255"`
+	Permissions []string `json:"permissions" example:"attendance:read"`
+}
+
+// AssignRoleRequest represents the request to assign a role to a user
+// @Description Role assignment request
+type AssignRoleRequest struct {
+	Role string `json:"role" example:"supervisor" binding:"required,max=50"`
+}
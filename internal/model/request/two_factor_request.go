@@ -0,0 +1,21 @@
+package request
+
+// TwoFactorCodeRequest represents a request confirmed with a TOTP or recovery code
+// @Description Two-factor code request
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" example:"123456" binding:"required"`
+}
+
+// DisableTwoFactorRequest represents the request to turn two-factor authentication off
+// @Description Disable two-factor authentication request
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" example:"secure123" binding:"required"`
+	Code     string `json:"code" example:"123456" binding:"required"`
+}
+
+// LoginTwoFactorRequest represents the second login step
+// @Description Two-factor login request; code is a TOTP code or an unused recovery code
+type LoginTwoFactorRequest struct {
+	Challenge string `json:"challenge" binding:"required"`
+	Code      string `json:"code" example:"123456" binding:"required"`
+}
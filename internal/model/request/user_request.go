@@ -1,19 +1,18 @@
 package request
 
 // RegisterRequest represents the user registration request
-// @Description User registration request
+// @Description User registration request; self-registered accounts always get the employee role
 type RegisterRequest struct {
 	Username string `json:"username" example:"john_doe" binding:"required"`
 	Password string `json:"password" example:"secure123" binding:"required"`
 	FullName string `json:"full_name" example:"John Doe" binding:"required"`
 	Email    string `json:"email" example:"john@example.com" binding:"required,email"`
-	Role     string `json:"role" example:"employee" binding:"required,oneof=admin employee"`
 }
 
 // LoginRequest represents the login credentials
 // @Description Login request
 type LoginRequest struct {
-	Username string `json:"username" example:"john_doe" binding:"required"`
+	Username string `json:"username" example:"john_doe" binding:"required,max=50"`
 	Password string `json:"password" example:"secure123" binding:"required"`
 }
 
@@ -23,5 +22,62 @@ type UpdateUserRequest struct {
 	Username string `json:"username" example:"john_doe" binding:"required"`
 	FullName string `json:"full_name" example:"John Doe Updated" binding:"required"`
 	Email    string `json:"email" example:"john.updated@example.com" binding:"required,email"`
-	Role     string `json:"role" example:"employee" binding:"required,oneof=admin employee"`
+	// Role is optional; changing it requires the roles:manage permission
+	Role string `json:"role" example:"employee" binding:"omitempty,max=50"`
+}
+
+// RefreshTokenRequest represents the request to exchange a refresh token for a new token pair
+// @Description Token refresh request
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest represents the logout request
+// @Description Logout request
+type LogoutRequest struct {
+	// RefreshToken is revoked along with the access token when given
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ChangePasswordRequest represents the request to change your own password
+// @Description Change password request
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" example:"secure123" binding:"required"`
+	NewPassword string `json:"new_password" example:"even-more-secure456" binding:"required,min=8"`
+}
+
+// ResetPasswordRequest represents an admin setting a new password for a user
+// @Description Admin password reset request
+type ResetPasswordRequest struct {
+	NewPassword string `json:"new_password" example:"temporary-secret1" binding:"required,min=8"`
+}
+
+// ForgotPasswordRequest represents the request to email a password reset link
+// @Description Forgot password request
+type ForgotPasswordRequest struct {
+	Email string `json:"email" example:"john@example.com" binding:"required,email"`
+}
+
+// ConfirmPasswordResetRequest represents the request to set a new password with a reset token
+// @Description Password reset confirmation request
+type ConfirmPasswordResetRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" example:"even-more-secure456" binding:"required,min=8"`
+}
+
+// CreateInvitationRequest represents the request to invite someone to create an account
+// @Description Invitation request
+type CreateInvitationRequest struct {
+	Email        string `json:"email" example:"jane@example.com" binding:"required,email,max=100"`
+	Role         string `json:"role" example:"employee" binding:"required,max=50"`
+	DepartmentID *uint  `json:"department_id" example:"1"`
+}
+
+// AcceptInvitationRequest represents the request to redeem an invitation
+// @Description Invitation acceptance request
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Username string `json:"username" example:"jane_doe" binding:"required,max=50"`
+	FullName string `json:"full_name" example:"Jane Doe" binding:"required,max=100"`
+	Password string `json:"password" example:"secure123" binding:"required,min=8"`
 }
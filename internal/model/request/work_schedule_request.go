@@ -0,0 +1,11 @@
+package request
+
+// WorkScheduleRequest represents the request body for setting a department's hours on one weekday
+type WorkScheduleRequest struct {
+	// StartTime is the expected check-in time (format: HH:MM or HH:MM:SS)
+	StartTime string `json:"start_time" example:"09:00" binding:"required"`
+	// EndTime is the expected check-out time (format: HH:MM or HH:MM:SS); an end before the start spans midnight
+	EndTime string `json:"end_time" example:"17:00" binding:"required"`
+	// GraceMinutes is how many minutes after the start time a check-in still counts as on time
+	GraceMinutes int `json:"grace_minutes" example:"10" binding:"min=0"`
+}
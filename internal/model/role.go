@@ -0,0 +1,125 @@
+package model
+
+import (
+	"time"
+)
+
+// Built-in role names; admin and employee always exist and cannot be deleted
+const (
+	RoleAdmin      = "admin"
+	RoleEmployee   = "employee"
+	RoleHRManager  = "hr_manager"
+	RoleSupervisor = "supervisor"
+	RoleAuditor    = "auditor"
+)
+
+// Permission strings granted to roles and carried in access tokens
+const (
+	// PermissionAll grants every permission, including ones added in later releases
+	PermissionAll = "*"
+
+	PermissionUsersRead                   = "users:read"
+	PermissionUsersManage                 = "users:manage"
+	PermissionRolesManage                 = "roles:manage"
+	PermissionAttendanceRead              = "attendance:read"
+	PermissionAttendanceApprove           = "attendance:approve"
+	PermissionAttendanceApproveDepartment = "attendance:approve:department"
+	PermissionAttendanceManage            = "attendance:manage"
+	PermissionLeavesRead                  = "leaves:read"
+	PermissionLeavesReadDepartment        = "leaves:read:department"
+	PermissionLeavesApprove               = "leaves:approve"
+	PermissionLeavesApproveDepartment     = "leaves:approve:department"
+	PermissionLeaveTypesManage            = "leave_types:manage"
+	PermissionDepartmentsManage           = "departments:manage"
+	PermissionHolidaysManage              = "holidays:manage"
+	PermissionGeofencesManage             = "geofences:manage"
+	PermissionAuditRead                   = "audit:read"
+)
+
+// Permissions lists every permission a role can be granted
+var Permissions = []string{
+	PermissionAll,
+	PermissionUsersRead,
+	PermissionUsersManage,
+	PermissionRolesManage,
+	PermissionAttendanceRead,
+	PermissionAttendanceApprove,
+	PermissionAttendanceApproveDepartment,
+	PermissionAttendanceManage,
+	PermissionLeavesRead,
+	PermissionLeavesReadDepartment,
+	PermissionLeavesApprove,
+	PermissionLeavesApproveDepartment,
+	PermissionLeaveTypesManage,
+	PermissionDepartmentsManage,
+	PermissionHolidaysManage,
+	PermissionGeofencesManage,
+	PermissionAuditRead,
+}
+
+// PrivilegedPermissions let their holders grant themselves any other permission, so only users who hold
+// them too may take over accounts that have them, e.g. by resetting their password
+var PrivilegedPermissions = []string{
+	PermissionAll,
+	PermissionRolesManage,
+}
+
+// Role is a named set of permissions; users are assigned a role through User.Role
+type Role struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"not null;size:50;uniqueIndex" json:"name"`
+	Description string    `gorm:"size:255" json:"description"`
+	Permissions []string  `gorm:"type:text;serializer:json" json:"permissions"`
+	CreatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// DefaultRoles returns the roles seeded into a fresh database
+func DefaultRoles() []Role {
+	return []Role{
+		{
+			Name:        RoleAdmin,
+			Description: "Full access to every feature",
+			Permissions: []string{PermissionAll},
+		},
+		{
+			Name:        RoleEmployee,
+			Description: "Records their own attendance and leave",
+			Permissions: []string{},
+		},
+		{
+			Name:        RoleHRManager,
+			Description: "Manages employees, leave and the holiday calendar",
+			Permissions: []string{
+				PermissionUsersRead,
+				PermissionUsersManage,
+				PermissionAttendanceRead,
+				PermissionAttendanceApprove,
+				PermissionLeavesRead,
+				PermissionLeavesApprove,
+				PermissionLeaveTypesManage,
+				PermissionDepartmentsManage,
+				PermissionHolidaysManage,
+			},
+		},
+		{
+			Name:        RoleSupervisor,
+			Description: "Reviews and decides on leave requests and attendance corrections of their own department",
+			Permissions: []string{
+				PermissionLeavesReadDepartment,
+				PermissionLeavesApproveDepartment,
+				PermissionAttendanceApproveDepartment,
+			},
+		},
+		{
+			Name:        RoleAuditor,
+			Description: "Read-only access to users, attendance, leave and the audit log",
+			Permissions: []string{
+				PermissionUsersRead,
+				PermissionAttendanceRead,
+				PermissionLeavesRead,
+				PermissionAuditRead,
+			},
+		},
+	}
+}
@@ -2,17 +2,35 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // User represents the user model
 // @Description User model
 type User struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Username  string    `gorm:"unique;not null;size:50" json:"username"`
-	Password  string    `gorm:"not null;size:255" json:"-"`
-	FullName  string    `gorm:"not null;size:100" json:"full_name"`
-	Email     string    `gorm:"unique;not null;size:100" json:"email"`
-	Role      string    `gorm:"not null;size:20;check:role IN ('admin', 'employee')" json:"role"`
-	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Username     string    `gorm:"unique;not null;size:50" json:"username"`
+	Password     string    `gorm:"not null;size:255" json:"-"`
+	FullName     string    `gorm:"not null;size:100" json:"full_name"`
+	Email        string    `gorm:"unique;not null;size:100" json:"email"`
+	Role         string    `gorm:"not null;size:50;index" json:"role"`
+	DepartmentID *uint     `gorm:"index" json:"department_id"`
+	CreatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+
+	// Inactive users keep their account and history but cannot log in. Deleted users are hidden from
+	// every query until restored; their username and email stay reserved.
+	Active    bool           `gorm:"not null;default:true" json:"active"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string"`
+
+	// Two-factor authentication: the secret is stored when enrollment starts, but TOTPEnabled is only
+	// set once the first code has been verified. TOTPLastStep is the time step of the last accepted
+	// code so that a code cannot be used twice.
+	TOTPEnabled  bool   `gorm:"column:totp_enabled;not null;default:false" json:"totp_enabled"`
+	TOTPSecret   string `gorm:"column:totp_secret;size:64" json:"-"`
+	TOTPLastStep int64  `gorm:"column:totp_last_step;not null;default:0" json:"-" audit:"-"`
+
+	// EmployeeDetail is only loaded when the profile is explicitly requested
+	EmployeeDetail *EmployeeDetail `gorm:"foreignKey:UserID" json:"employee_detail,omitempty"`
 }
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// WorkSchedule defines the expected working hours of a department on one day of the week
+type WorkSchedule struct {
+	ID           uint `gorm:"primaryKey" json:"id"`
+	DepartmentID uint `gorm:"not null;uniqueIndex:idx_work_schedule_department_day" json:"department_id"`
+	// DayOfWeek follows ISO 8601: 1 is Monday, 7 is Sunday
+	DayOfWeek int `gorm:"not null;uniqueIndex:idx_work_schedule_department_day;check:day_of_week BETWEEN 1 AND 7" json:"day_of_week"`
+	// StartTime and EndTime are wall-clock times formatted as HH:MM:SS
+	StartTime string `gorm:"type:time;not null" json:"start_time"`
+	EndTime   string `gorm:"type:time;not null" json:"end_time"`
+	// GraceMinutes is how long after StartTime a check-in still counts as on time
+	GraceMinutes int       `gorm:"not null;default:0" json:"grace_minutes"`
+	CreatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type AttendanceBreakRepository interface {
+	Create(ctx context.Context, attendanceBreak *model.AttendanceBreak) error
+	Update(ctx context.Context, attendanceBreak *model.AttendanceBreak) error
+	// GetOpen returns the running break of an attendance session
+	GetOpen(ctx context.Context, attendanceID uint) (*model.AttendanceBreak, error)
+	// FindByAttendanceID returns the breaks of an attendance session in the order they were taken
+	FindByAttendanceID(ctx context.Context, attendanceID uint) ([]model.AttendanceBreak, error)
+}
+
+type attendanceBreakRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceBreakRepository(db *gorm.DB) AttendanceBreakRepository {
+	return &attendanceBreakRepository{db: db}
+}
+
+func (r *attendanceBreakRepository) Create(ctx context.Context, attendanceBreak *model.AttendanceBreak) error {
+	return r.db.WithContext(ctx).Create(attendanceBreak).Error
+}
+
+func (r *attendanceBreakRepository) Update(ctx context.Context, attendanceBreak *model.AttendanceBreak) error {
+	return r.db.WithContext(ctx).Save(attendanceBreak).Error
+}
+
+func (r *attendanceBreakRepository) GetOpen(ctx context.Context, attendanceID uint) (*model.AttendanceBreak, error) {
+	var attendanceBreak model.AttendanceBreak
+	err := r.db.WithContext(ctx).
+		Where("attendance_id = ? AND ended_at IS NULL", attendanceID).
+		Order("started_at DESC").
+		First(&attendanceBreak).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attendanceBreak, nil
+}
+
+func (r *attendanceBreakRepository) FindByAttendanceID(ctx context.Context, attendanceID uint) ([]model.AttendanceBreak, error) {
+	var breaks []model.AttendanceBreak
+	err := r.db.WithContext(ctx).
+		Where("attendance_id = ?", attendanceID).
+		Order("started_at").
+		Find(&breaks).Error
+	return breaks, err
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// AttendanceCorrectionFilter narrows down the listed corrections; zero values match everything
+type AttendanceCorrectionFilter struct {
+	UserID *uint
+	// DepartmentID only matches corrections of the department's current members
+	DepartmentID *uint
+	Status       string
+}
+
+type AttendanceCorrectionRepository interface {
+	Create(ctx context.Context, correction *model.AttendanceCorrection) error
+	GetByID(ctx context.Context, id uint) (*model.AttendanceCorrection, error)
+	// HasPending reports whether the attendance already has a correction waiting for a decision
+	HasPending(ctx context.Context, attendanceID uint) (bool, error)
+	Find(ctx context.Context, filter AttendanceCorrectionFilter) ([]model.AttendanceCorrection, error)
+	// Decide stores the decision of a pending correction and, when attendance is not nil, the corrected
+	// attendance in one transaction. It reports false when the correction was no longer pending.
+	Decide(ctx context.Context, correction *model.AttendanceCorrection, attendance *model.Attendance) (bool, error)
+}
+
+type attendanceCorrectionRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceCorrectionRepository(db *gorm.DB) AttendanceCorrectionRepository {
+	return &attendanceCorrectionRepository{db: db}
+}
+
+func (r *attendanceCorrectionRepository) Create(ctx context.Context, correction *model.AttendanceCorrection) error {
+	return r.db.WithContext(ctx).Omit("User").Create(correction).Error
+}
+
+func (r *attendanceCorrectionRepository) GetByID(ctx context.Context, id uint) (*model.AttendanceCorrection, error) {
+	var correction model.AttendanceCorrection
+	err := r.db.WithContext(ctx).Preload("User", withDeleted).First(&correction, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &correction, nil
+}
+
+func (r *attendanceCorrectionRepository) HasPending(ctx context.Context, attendanceID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.AttendanceCorrection{}).
+		Where("attendance_id = ? AND status = ?", attendanceID, model.CorrectionStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *attendanceCorrectionRepository) Find(ctx context.Context, filter AttendanceCorrectionFilter) ([]model.AttendanceCorrection, error) {
+	query := r.db.WithContext(ctx).Preload("User", withDeleted)
+	if filter.UserID != nil {
+		query = query.Where("attendance_corrections.user_id = ?", *filter.UserID)
+	}
+	if filter.DepartmentID != nil {
+		query = query.
+			Joins("JOIN users ON users.id = attendance_corrections.user_id").
+			Where("users.department_id = ?", *filter.DepartmentID)
+	}
+	if filter.Status != "" {
+		query = query.Where("attendance_corrections.status = ?", filter.Status)
+	}
+
+	var corrections []model.AttendanceCorrection
+	err := query.Order("attendance_corrections.created_at DESC").Find(&corrections).Error
+	return corrections, err
+}
+
+func (r *attendanceCorrectionRepository) Decide(ctx context.Context, correction *model.AttendanceCorrection, attendance *model.Attendance) (bool, error) {
+	decided := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.AttendanceCorrection{}).
+			Where("id = ? AND status = ?", correction.ID, model.CorrectionStatusPending).
+			Updates(map[string]interface{}{
+				"status":             correction.Status,
+				"decided_by":         correction.DecidedBy,
+				"decided_at":         correction.DecidedAt,
+				"original_check_in":  correction.OriginalCheckIn,
+				"original_check_out": correction.OriginalCheckOut,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		decided = true
+		if attendance == nil {
+			return nil
+		}
+		return tx.Omit("User", "Breaks").Save(attendance).Error
+	})
+	return decided, err
+}
@@ -11,12 +11,30 @@ import (
 type AttendanceRepository interface {
 	Create(ctx context.Context, attendance *model.Attendance) error
 	GetByID(ctx context.Context, id uint) (*model.Attendance, error)
-	GetByUserIDAndDate(ctx context.Context, userID uint, date time.Time) (*model.Attendance, error)
+	// GetByIDWithDeleted also finds deleted records
+	GetByIDWithDeleted(ctx context.Context, id uint) (*model.Attendance, error)
+	// FindByUserIDAndDate returns the user's records of a workday ordered by check-in, days without one first
+	FindByUserIDAndDate(ctx context.Context, userID uint, date time.Time) ([]model.Attendance, error)
+	// GetOpenSession returns the user's latest session checked in since the given time and not checked out yet
+	GetOpenSession(ctx context.Context, userID uint, since time.Time) (*model.Attendance, error)
+	// FindOverlapping returns the user's sessions other than excludeID that overlap [start, end). Open
+	// sessions checked in since openSince count as still running.
+	FindOverlapping(ctx context.Context, userID, excludeID uint, start, end, openSince time.Time) ([]model.Attendance, error)
 	Update(ctx context.Context, attendance *model.Attendance) error
 	Delete(ctx context.Context, id uint) error
+	// Restore undoes a soft delete, reporting false when no deleted record has this ID
+	Restore(ctx context.Context, id uint) (bool, error)
 	GetUserAttendances(ctx context.Context, userID uint, startDate, endDate time.Time) ([]model.Attendance, error)
 }
 
+// openSession matches sessions without a check-out
+const openSession = "check_out IS NULL"
+
+// byStart orders preloaded breaks the way they were taken
+func byStart(db *gorm.DB) *gorm.DB {
+	return db.Order("started_at")
+}
+
 type attendanceRepository struct {
 	db *gorm.DB
 }
@@ -31,20 +49,38 @@ func (r *attendanceRepository) Create(ctx context.Context, attendance *model.Att
 
 func (r *attendanceRepository) GetByID(ctx context.Context, id uint) (*model.Attendance, error) {
 	var attendance model.Attendance
-	err := r.db.WithContext(ctx).First(&attendance, id).Error
+	err := r.db.WithContext(ctx).Preload("Breaks", byStart).First(&attendance, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &attendance, nil
 }
 
-func (r *attendanceRepository) GetByUserIDAndDate(ctx context.Context, userID uint, date time.Time) (*model.Attendance, error) {
+func (r *attendanceRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*model.Attendance, error) {
 	var attendance model.Attendance
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
+	err := r.db.WithContext(ctx).Unscoped().Preload("Breaks", byStart).First(&attendance, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attendance, nil
+}
 
+func (r *attendanceRepository) FindByUserIDAndDate(ctx context.Context, userID uint, date time.Time) ([]model.Attendance, error) {
+	var attendances []model.Attendance
 	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND check_in >= ? AND check_in < ?", userID, startOfDay, endOfDay).
+		Where("user_id = ? AND date = ?", userID, date.Format("2006-01-02")).
+		Order("check_in, id").
+		Find(&attendances).Error
+	return attendances, err
+}
+
+func (r *attendanceRepository) GetOpenSession(ctx context.Context, userID uint, since time.Time) (*model.Attendance, error) {
+	var attendance model.Attendance
+	err := r.db.WithContext(ctx).
+		Preload("Breaks", byStart).
+		Where("user_id = ? AND check_in >= ?", userID, since).
+		Where(openSession).
+		Order("check_in DESC").
 		First(&attendance).Error
 	if err != nil {
 		return nil, err
@@ -52,18 +88,38 @@ func (r *attendanceRepository) GetByUserIDAndDate(ctx context.Context, userID ui
 	return &attendance, nil
 }
 
+func (r *attendanceRepository) FindOverlapping(ctx context.Context, userID, excludeID uint, start, end, openSince time.Time) ([]model.Attendance, error) {
+	var attendances []model.Attendance
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND id <> ? AND check_in IS NOT NULL AND check_in < ?", userID, excludeID, end).
+		Where("check_out > ? OR ("+openSession+" AND check_in >= ?)", start, openSince).
+		Find(&attendances).Error
+	return attendances, err
+}
+
 func (r *attendanceRepository) Update(ctx context.Context, attendance *model.Attendance) error {
-	return r.db.WithContext(ctx).Save(attendance).Error
+	return r.db.WithContext(ctx).Omit("User", "Breaks").Save(attendance).Error
 }
 
 func (r *attendanceRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&model.Attendance{}, id).Error
 }
 
+func (r *attendanceRepository) Restore(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&model.Attendance{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	return result.RowsAffected == 1, result.Error
+}
+
 func (r *attendanceRepository) GetUserAttendances(ctx context.Context, userID uint, startDate, endDate time.Time) ([]model.Attendance, error) {
 	var attendances []model.Attendance
 	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND check_in >= ? AND check_in <= ?", userID, startDate, endDate).
+		Preload("Breaks", byStart).
+		Where("user_id = ? AND date >= ? AND date < ?", userID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Order("date, check_in").
 		Find(&attendances).Error
 	return attendances, err
 }
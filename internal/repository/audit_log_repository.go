@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter selects one page of audit log entries; zero values match everything
+type AuditLogFilter struct {
+	ActorID  *uint
+	Entity   string
+	EntityID string
+	Action   string
+	From     time.Time
+	To       time.Time
+	Page     int
+	PageSize int
+}
+
+// AuditLogRepository only reads the audit log; entries are written by the audit plugin of the database
+type AuditLogRepository interface {
+	// List returns one page of entries matching the filter, newest first, and the number of matching entries
+	List(ctx context.Context, filter AuditLogFilter) ([]model.AuditLog, int64, error)
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) List(ctx context.Context, filter AuditLogFilter) ([]model.AuditLog, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.AuditLog{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Entity != "" {
+		query = query.Where("entity = ?", filter.Entity)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []model.AuditLog
+	err := query.Order("created_at DESC, id DESC").Offset((filter.Page - 1) * filter.PageSize).Limit(filter.PageSize).Find(&entries).Error
+	return entries, total, err
+}
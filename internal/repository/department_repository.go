@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type DepartmentRepository interface {
+	Create(ctx context.Context, department *model.Department) error
+	GetByID(ctx context.Context, id uint) (*model.Department, error)
+	GetAll(ctx context.Context) ([]model.Department, error)
+	Update(ctx context.Context, department *model.Department) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type departmentRepository struct {
+	db *gorm.DB
+}
+
+func NewDepartmentRepository(db *gorm.DB) DepartmentRepository {
+	return &departmentRepository{db: db}
+}
+
+func (r *departmentRepository) Create(ctx context.Context, department *model.Department) error {
+	return r.db.WithContext(ctx).Create(department).Error
+}
+
+func (r *departmentRepository) GetByID(ctx context.Context, id uint) (*model.Department, error) {
+	var department model.Department
+	err := r.db.WithContext(ctx).First(&department, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &department, nil
+}
+
+func (r *departmentRepository) GetAll(ctx context.Context) ([]model.Department, error) {
+	var departments []model.Department
+	err := r.db.WithContext(ctx).Order("name").Find(&departments).Error
+	return departments, err
+}
+
+func (r *departmentRepository) Update(ctx context.Context, department *model.Department) error {
+	return r.db.WithContext(ctx).Save(department).Error
+}
+
+func (r *departmentRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Department{}, id).Error
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type EmployeeDetailRepository interface {
+	Create(ctx context.Context, detail *model.EmployeeDetail) error
+	GetByUserID(ctx context.Context, userID uint) (*model.EmployeeDetail, error)
+	GetByEmployeeID(ctx context.Context, employeeID string) (*model.EmployeeDetail, error)
+	Update(ctx context.Context, detail *model.EmployeeDetail) error
+	DeleteByUserID(ctx context.Context, userID uint) error
+}
+
+type employeeDetailRepository struct {
+	db *gorm.DB
+}
+
+func NewEmployeeDetailRepository(db *gorm.DB) EmployeeDetailRepository {
+	return &employeeDetailRepository{db: db}
+}
+
+func (r *employeeDetailRepository) Create(ctx context.Context, detail *model.EmployeeDetail) error {
+	return r.db.WithContext(ctx).Create(detail).Error
+}
+
+func (r *employeeDetailRepository) GetByUserID(ctx context.Context, userID uint) (*model.EmployeeDetail, error) {
+	var detail model.EmployeeDetail
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&detail).Error
+	if err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+func (r *employeeDetailRepository) GetByEmployeeID(ctx context.Context, employeeID string) (*model.EmployeeDetail, error) {
+	var detail model.EmployeeDetail
+	err := r.db.WithContext(ctx).Where("employee_id = ?", employeeID).First(&detail).Error
+	if err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+func (r *employeeDetailRepository) Update(ctx context.Context, detail *model.EmployeeDetail) error {
+	return r.db.WithContext(ctx).Save(detail).Error
+}
+
+func (r *employeeDetailRepository) DeleteByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.EmployeeDetail{}).Error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type GeofenceRepository interface {
+	Create(ctx context.Context, geofence *model.Geofence) error
+	GetByID(ctx context.Context, id uint) (*model.Geofence, error)
+	GetAll(ctx context.Context) ([]model.Geofence, error)
+	Update(ctx context.Context, geofence *model.Geofence) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type geofenceRepository struct {
+	db *gorm.DB
+}
+
+func NewGeofenceRepository(db *gorm.DB) GeofenceRepository {
+	return &geofenceRepository{db: db}
+}
+
+func (r *geofenceRepository) Create(ctx context.Context, geofence *model.Geofence) error {
+	return r.db.WithContext(ctx).Create(geofence).Error
+}
+
+func (r *geofenceRepository) GetByID(ctx context.Context, id uint) (*model.Geofence, error) {
+	var geofence model.Geofence
+	err := r.db.WithContext(ctx).First(&geofence, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &geofence, nil
+}
+
+func (r *geofenceRepository) GetAll(ctx context.Context) ([]model.Geofence, error) {
+	var geofences []model.Geofence
+	err := r.db.WithContext(ctx).Order("name").Find(&geofences).Error
+	return geofences, err
+}
+
+func (r *geofenceRepository) Update(ctx context.Context, geofence *model.Geofence) error {
+	return r.db.WithContext(ctx).Save(geofence).Error
+}
+
+func (r *geofenceRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Geofence{}, id).Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type HolidayRepository interface {
+	Create(ctx context.Context, holiday *model.Holiday) error
+	GetByID(ctx context.Context, id uint) (*model.Holiday, error)
+	GetByDate(ctx context.Context, date time.Time) (*model.Holiday, error)
+	GetBetween(ctx context.Context, startDate, endDate time.Time) ([]model.Holiday, error)
+	Update(ctx context.Context, holiday *model.Holiday) error
+	Delete(ctx context.Context, id uint) error
+	// Import stores holidays in one transaction, first deleting existing ones between
+	// startDate and endDate when replace is set
+	Import(ctx context.Context, holidays []model.Holiday, startDate, endDate time.Time, replace bool) error
+}
+
+type holidayRepository struct {
+	db *gorm.DB
+}
+
+func NewHolidayRepository(db *gorm.DB) HolidayRepository {
+	return &holidayRepository{db: db}
+}
+
+func (r *holidayRepository) Create(ctx context.Context, holiday *model.Holiday) error {
+	return r.db.WithContext(ctx).Create(holiday).Error
+}
+
+func (r *holidayRepository) GetByID(ctx context.Context, id uint) (*model.Holiday, error) {
+	var holiday model.Holiday
+	err := r.db.WithContext(ctx).First(&holiday, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+func (r *holidayRepository) GetByDate(ctx context.Context, date time.Time) (*model.Holiday, error) {
+	var holiday model.Holiday
+	err := r.db.WithContext(ctx).Where("date = ?", date.Format("2006-01-02")).First(&holiday).Error
+	if err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+func (r *holidayRepository) GetBetween(ctx context.Context, startDate, endDate time.Time) ([]model.Holiday, error) {
+	var holidays []model.Holiday
+	err := r.db.WithContext(ctx).
+		Where("date >= ? AND date <= ?", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Order("date").
+		Find(&holidays).Error
+	return holidays, err
+}
+
+func (r *holidayRepository) Update(ctx context.Context, holiday *model.Holiday) error {
+	return r.db.WithContext(ctx).Save(holiday).Error
+}
+
+func (r *holidayRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Holiday{}, id).Error
+}
+
+func (r *holidayRepository) Import(ctx context.Context, holidays []model.Holiday, startDate, endDate time.Time, replace bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if replace {
+			err := tx.Where("date >= ? AND date <= ?", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+				Delete(&model.Holiday{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Create(&holidays).Error
+	})
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *model.Invitation) error
+	GetByID(ctx context.Context, id uint) (*model.Invitation, error)
+	GetByHash(ctx context.Context, hash string) (*model.Invitation, error)
+	GetAll(ctx context.Context) ([]model.Invitation, error)
+	// Revoke withdraws a pending invitation, reporting false when it was already accepted or revoked
+	Revoke(ctx context.Context, id uint) (bool, error)
+	// RevokePendingForEmail withdraws every open invitation sent to the email address
+	RevokePendingForEmail(ctx context.Context, email string) error
+	// Accept consumes the invitation and creates its user in one transaction, reporting false when
+	// the invitation was accepted or revoked in the meantime
+	Accept(ctx context.Context, id uint, user *model.User) (bool, error)
+}
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *model.Invitation) error {
+	return r.db.WithContext(ctx).Create(invitation).Error
+}
+
+func (r *invitationRepository) GetByID(ctx context.Context, id uint) (*model.Invitation, error) {
+	var invitation model.Invitation
+	err := r.db.WithContext(ctx).First(&invitation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) GetByHash(ctx context.Context, hash string) (*model.Invitation, error) {
+	var invitation model.Invitation
+	err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) GetAll(ctx context.Context) ([]model.Invitation, error) {
+	var invitations []model.Invitation
+	err := r.db.WithContext(ctx).Order("created_at DESC").Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *invitationRepository) Revoke(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *invitationRepository) RevokePendingForEmail(ctx context.Context, email string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.Invitation{}).
+		Where("email = ? AND accepted_at IS NULL AND revoked_at IS NULL", email).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *invitationRepository) Accept(ctx context.Context, id uint, user *model.User) (bool, error) {
+	accepted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Invitation{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+			Update("accepted_at", time.Now())
+		if result.Error != nil || result.RowsAffected != 1 {
+			return result.Error
+		}
+
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		accepted = true
+		return tx.Model(&model.Invitation{}).Where("id = ?", id).Update("user_id", user.ID).Error
+	})
+	return accepted, err
+}
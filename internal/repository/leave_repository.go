@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type LeaveRepository interface {
+	CreateType(ctx context.Context, leaveType *model.LeaveType) error
+	GetTypeByID(ctx context.Context, id uint) (*model.LeaveType, error)
+	GetTypes(ctx context.Context) ([]model.LeaveType, error)
+	Create(ctx context.Context, leave *model.LeaveRequest) error
+	GetByID(ctx context.Context, id uint) (*model.LeaveRequest, error)
+	Update(ctx context.Context, leave *model.LeaveRequest) error
+	GetByStatus(ctx context.Context, status string) ([]model.LeaveRequest, error)
+	GetUserLeaves(ctx context.Context, userID uint) ([]model.LeaveRequest, error)
+	GetUserLeavesByStatus(ctx context.Context, userID uint, status string) ([]model.LeaveRequest, error)
+	GetByDepartment(ctx context.Context, departmentID uint, status string) ([]model.LeaveRequest, error)
+	GetOverlapping(ctx context.Context, userID uint, startDate, endDate time.Time) ([]model.LeaveRequest, error)
+	GetApprovedOn(ctx context.Context, userID uint, date time.Time) (*model.LeaveRequest, error)
+}
+
+type leaveRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveRepository(db *gorm.DB) LeaveRepository {
+	return &leaveRepository{db: db}
+}
+
+func (r *leaveRepository) CreateType(ctx context.Context, leaveType *model.LeaveType) error {
+	return r.db.WithContext(ctx).Create(leaveType).Error
+}
+
+func (r *leaveRepository) GetTypeByID(ctx context.Context, id uint) (*model.LeaveType, error) {
+	var leaveType model.LeaveType
+	err := r.db.WithContext(ctx).First(&leaveType, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &leaveType, nil
+}
+
+func (r *leaveRepository) GetTypes(ctx context.Context) ([]model.LeaveType, error) {
+	var leaveTypes []model.LeaveType
+	err := r.db.WithContext(ctx).Order("name").Find(&leaveTypes).Error
+	return leaveTypes, err
+}
+
+func (r *leaveRepository) Create(ctx context.Context, leave *model.LeaveRequest) error {
+	return r.db.WithContext(ctx).Create(leave).Error
+}
+
+func (r *leaveRepository) GetByID(ctx context.Context, id uint) (*model.LeaveRequest, error) {
+	var leave model.LeaveRequest
+	err := r.db.WithContext(ctx).Preload("LeaveType").First(&leave, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &leave, nil
+}
+
+// withDeleted lets preloads include soft-deleted users so history of departed employees stays complete
+func withDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+func (r *leaveRepository) Update(ctx context.Context, leave *model.LeaveRequest) error {
+	return r.db.WithContext(ctx).Omit("User", "LeaveType").Save(leave).Error
+}
+
+func (r *leaveRepository) GetByStatus(ctx context.Context, status string) ([]model.LeaveRequest, error) {
+	var leaves []model.LeaveRequest
+	query := r.db.WithContext(ctx).Preload("User", withDeleted).Preload("LeaveType")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("start_date DESC").Find(&leaves).Error
+	return leaves, err
+}
+
+func (r *leaveRepository) GetUserLeaves(ctx context.Context, userID uint) ([]model.LeaveRequest, error) {
+	return r.GetUserLeavesByStatus(ctx, userID, "")
+}
+
+// GetUserLeavesByStatus returns the user's leave requests, optionally filtered by status
+func (r *leaveRepository) GetUserLeavesByStatus(ctx context.Context, userID uint, status string) ([]model.LeaveRequest, error) {
+	var leaves []model.LeaveRequest
+	query := r.db.WithContext(ctx).
+		Preload("LeaveType").
+		Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("start_date DESC").Find(&leaves).Error
+	return leaves, err
+}
+
+// GetByDepartment returns the leave requests of a department's members, optionally filtered by status
+func (r *leaveRepository) GetByDepartment(ctx context.Context, departmentID uint, status string) ([]model.LeaveRequest, error) {
+	var leaves []model.LeaveRequest
+	query := r.db.WithContext(ctx).
+		Preload("User", withDeleted).
+		Preload("LeaveType").
+		Joins("JOIN users ON users.id = leave_requests.user_id").
+		Where("users.department_id = ?", departmentID)
+	if status != "" {
+		query = query.Where("leave_requests.status = ?", status)
+	}
+	err := query.Order("leave_requests.start_date DESC").Find(&leaves).Error
+	return leaves, err
+}
+
+// GetOverlapping returns the user's pending or approved leaves that overlap the given date range
+func (r *leaveRepository) GetOverlapping(ctx context.Context, userID uint, startDate, endDate time.Time) ([]model.LeaveRequest, error) {
+	var leaves []model.LeaveRequest
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status IN ? AND start_date <= ? AND end_date >= ?",
+			userID, []string{model.LeaveStatusPending, model.LeaveStatusApproved},
+			endDate.Format("2006-01-02"), startDate.Format("2006-01-02")).
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// GetApprovedOn returns the user's approved leave covering the given day
+func (r *leaveRepository) GetApprovedOn(ctx context.Context, userID uint, date time.Time) (*model.LeaveRequest, error) {
+	var leave model.LeaveRequest
+	day := date.Format("2006-01-02")
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ? AND start_date <= ? AND end_date >= ?", userID, model.LeaveStatusApproved, day, day).
+		First(&leave).Error
+	if err != nil {
+		return nil, err
+	}
+	return &leave, nil
+}
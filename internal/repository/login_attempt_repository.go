@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ErrLoginLockTimeout is returned when concurrent attempts hold a username or IP address for too long
+var ErrLoginLockTimeout = errors.New("timed out waiting for concurrent login attempts")
+
+// loginLockTimeout is how many seconds Reserve waits for the lock of a username or IP address
+const loginLockTimeout = 10
+
+// LoginAttemptFilter narrows down the attempts returned for review; zero values match everything
+type LoginAttemptFilter struct {
+	Username string
+	IP       string
+	Outcome  string
+	From     time.Time
+	To       time.Time
+	Limit    int
+}
+
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *model.LoginAttempt) error
+	// Reserve passes the uncleared failures of the attempt's username and IP address since the given time,
+	// newest first, to allow and inserts the attempt unless allow returns an error. Reservations for the same
+	// username or IP address are serialized with named locks, so each one counts those before it.
+	Reserve(ctx context.Context, attempt *model.LoginAttempt, since time.Time, allow func(failures, ipFailures []model.LoginAttempt) error) error
+	UpdateOutcome(ctx context.Context, id uint, outcome string) error
+	Delete(ctx context.Context, id uint) error
+	// ClearUsername and ClearIP stop earlier failures from counting towards a lockout
+	ClearUsername(ctx context.Context, username string) error
+	ClearIP(ctx context.Context, ip string) error
+	Find(ctx context.Context, filter LoginAttemptFilter) ([]model.LoginAttempt, error)
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Create(ctx context.Context, attempt *model.LoginAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+func (r *loginAttemptRepository) Reserve(ctx context.Context, attempt *model.LoginAttempt, since time.Time, allow func(failures, ipFailures []model.LoginAttempt) error) error {
+	// Named locks belong to the MySQL session, so taking, using and releasing them needs a single connection.
+	// Locks are always taken IP first, so two attempts never wait on each other's second lock.
+	return r.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		for _, name := range []string{loginLockName("ip", attempt.IP), loginLockName("username", attempt.Username)} {
+			var acquired *int
+			if err := conn.Raw("SELECT GET_LOCK(?, ?)", name, loginLockTimeout).Scan(&acquired).Error; err != nil {
+				return err
+			}
+			if acquired == nil || *acquired != 1 {
+				return ErrLoginLockTimeout
+			}
+			// The connection returns to the pool afterwards, so the lock must be released even if ctx is done
+			defer conn.WithContext(context.WithoutCancel(ctx)).Exec("SELECT RELEASE_LOCK(?)", name)
+		}
+
+		// Reserved attempts are stored as failures until resolved, so pending ones count as well
+		failures, err := recentFailures(conn, "username = ?", attempt.Username, since)
+		if err != nil {
+			return err
+		}
+		ipFailures, err := recentFailures(conn, "ip = ?", attempt.IP, since)
+		if err != nil {
+			return err
+		}
+		if err := allow(failures, ipFailures); err != nil {
+			return err
+		}
+		return conn.Create(attempt).Error
+	})
+}
+
+// loginLockName hashes the value so arbitrary usernames fit MySQL's 64 character lock names
+func loginLockName(kind, value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "login:" + kind + ":" + hex.EncodeToString(sum[:16])
+}
+
+func (r *loginAttemptRepository) UpdateOutcome(ctx context.Context, id uint, outcome string) error {
+	return r.db.WithContext(ctx).Model(&model.LoginAttempt{}).Where("id = ?", id).Update("outcome", outcome).Error
+}
+
+func (r *loginAttemptRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.LoginAttempt{}, id).Error
+}
+
+// recentFailures returns the uncleared failures matching the query since the given time, newest first
+func recentFailures(db *gorm.DB, query string, value string, since time.Time) ([]model.LoginAttempt, error) {
+	var attempts []model.LoginAttempt
+	err := db.
+		Where(query, value).
+		Where("outcome = ? AND cleared_at IS NULL AND created_at >= ?", model.LoginOutcomeFailure, since).
+		Order("created_at DESC").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *loginAttemptRepository) ClearUsername(ctx context.Context, username string) error {
+	return r.clear(ctx, "username = ?", username)
+}
+
+func (r *loginAttemptRepository) ClearIP(ctx context.Context, ip string) error {
+	return r.clear(ctx, "ip = ?", ip)
+}
+
+func (r *loginAttemptRepository) clear(ctx context.Context, query string, value string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.LoginAttempt{}).
+		Where(query, value).
+		Where("outcome = ? AND cleared_at IS NULL", model.LoginOutcomeFailure).
+		Update("cleared_at", time.Now()).Error
+}
+
+func (r *loginAttemptRepository) Find(ctx context.Context, filter LoginAttemptFilter) ([]model.LoginAttempt, error) {
+	query := r.db.WithContext(ctx).Model(&model.LoginAttempt{})
+	if filter.Username != "" {
+		query = query.Where("username = ?", filter.Username)
+	}
+	if filter.IP != "" {
+		query = query.Where("ip = ?", filter.IP)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var attempts []model.LoginAttempt
+	err := query.Order("created_at DESC").Find(&attempts).Error
+	return attempts, err
+}
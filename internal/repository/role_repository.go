@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	Create(ctx context.Context, role *model.Role) error
+	GetByID(ctx context.Context, id uint) (*model.Role, error)
+	GetByName(ctx context.Context, name string) (*model.Role, error)
+	GetAll(ctx context.Context) ([]model.Role, error)
+	Update(ctx context.Context, role *model.Role) error
+	Delete(ctx context.Context, id uint) error
+	// CountUsers returns how many users are assigned the role with the given name
+	CountUsers(ctx context.Context, name string) (int64, error)
+	// GetUserIDs returns the IDs of the users assigned the role with the given name
+	GetUserIDs(ctx context.Context, name string) ([]uint, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) Create(ctx context.Context, role *model.Role) error {
+	return r.db.WithContext(ctx).Create(role).Error
+}
+
+func (r *roleRepository) GetByID(ctx context.Context, id uint) (*model.Role, error) {
+	var role model.Role
+	err := r.db.WithContext(ctx).First(&role, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) GetByName(ctx context.Context, name string) (*model.Role, error) {
+	var role model.Role
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) GetAll(ctx context.Context) ([]model.Role, error) {
+	var roles []model.Role
+	err := r.db.WithContext(ctx).Order("name").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) Update(ctx context.Context, role *model.Role) error {
+	return r.db.WithContext(ctx).Save(role).Error
+}
+
+func (r *roleRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Role{}, id).Error
+}
+
+func (r *roleRepository) CountUsers(ctx context.Context, name string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
+
+func (r *roleRepository) GetUserIDs(ctx context.Context, name string) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("role = ?", name).Pluck("id", &ids).Error
+	return ids, err
+}
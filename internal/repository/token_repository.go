@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*model.RefreshToken, error)
+	// RevokeRefreshToken marks the token as used, reporting false when it was already revoked
+	RevokeRefreshToken(ctx context.Context, id uint) (bool, error)
+	RevokeUserRefreshTokens(ctx context.Context, userID uint) error
+	CreateRevokedToken(ctx context.Context, token *model.RevokedToken) error
+	// IsRevoked reports whether the access token with the given ID, issued to the user at issuedAt, is denylisted
+	IsRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error)
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *tokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *tokenRepository) RevokeRefreshToken(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *tokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).
+		Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *tokenRepository) CreateRevokedToken(ctx context.Context, token *model.RevokedToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *tokenRepository) IsRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error) {
+	// Revocations are stored with microseconds and tokens carry their issue time in milliseconds, so only
+	// tokens issued in the same millisecond as a revocation are rejected although issued just after it
+	match := r.db.Where("jti = '' AND user_id = ? AND created_at >= ?", userID, issuedAt)
+	if jti != "" {
+		match = match.Or("jti = ?", jti)
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.RevokedToken{}).
+		Where("expires_at > ?", time.Now()).
+		Where(match).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteExpired drops denylist entries and refresh tokens that can no longer be used anyway
+func (r *tokenRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	if err := r.db.WithContext(ctx).Where("expires_at <= ?", before).Delete(&model.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Where("expires_at <= ?", before).Delete(&model.RefreshToken{}).Error
+}
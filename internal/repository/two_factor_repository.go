@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type TwoFactorRepository interface {
+	// ReplaceRecoveryCodes deletes the user's recovery codes and stores the given ones instead
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codes []model.RecoveryCode) error
+	DeleteRecoveryCodes(ctx context.Context, userID uint) error
+	// UseRecoveryCode consumes an unused recovery code, reporting false when there is none with this hash
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error)
+	CreateChallenge(ctx context.Context, challenge *model.LoginChallenge) error
+	GetChallengeByHash(ctx context.Context, hash string) (*model.LoginChallenge, error)
+	// AddChallengeAttempt counts a code attempt, reporting false once the challenge has used up its attempts
+	AddChallengeAttempt(ctx context.Context, id uint, maxAttempts int) (bool, error)
+	// ConsumeChallenge marks the challenge as used, reporting false when it had already been used
+	ConsumeChallenge(ctx context.Context, id uint) (bool, error)
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codes []model.RecoveryCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *twoFactorRepository) DeleteRecoveryCodes(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+}
+
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *twoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *twoFactorRepository) CreateChallenge(ctx context.Context, challenge *model.LoginChallenge) error {
+	return r.db.WithContext(ctx).Create(challenge).Error
+}
+
+func (r *twoFactorRepository) GetChallengeByHash(ctx context.Context, hash string) (*model.LoginChallenge, error) {
+	var challenge model.LoginChallenge
+	err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&challenge).Error
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *twoFactorRepository) AddChallengeAttempt(ctx context.Context, id uint, maxAttempts int) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.LoginChallenge{}).
+		Where("id = ? AND attempts < ? AND used_at IS NULL", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *twoFactorRepository) ConsumeChallenge(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.LoginChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
@@ -2,18 +2,69 @@ package repository
 
 import (
 	"context"
+	"strings"
 
 	"absence/internal/model"
 
 	"gorm.io/gorm"
 )
 
+// UserSortFields are the columns the user list can be sorted by
+var UserSortFields = []string{"id", "username", "full_name", "email", "role", "created_at"}
+
+// User statuses the user list can be filtered by
+const (
+	UserStatusActive   = "active"
+	UserStatusInactive = "inactive"
+	UserStatusDeleted  = "deleted"
+)
+
+// UserFilter selects one page of users; zero values match every user that is not deleted
+type UserFilter struct {
+	// Search matches part of the full name, email or username
+	Search       string
+	Role         string
+	DepartmentID *uint
+	// Status is one of the UserStatus values
+	Status string
+	// Sort is one of UserSortFields
+	Sort       string
+	Descending bool
+	Page       int
+	PageSize   int
+}
+
 type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	GetByID(ctx context.Context, id uint) (*model.User, error)
+	// GetByIDWithDeleted also finds deleted users
+	GetByIDWithDeleted(ctx context.Context, id uint) (*model.User, error)
+	// UsernameExists and EmailExists also see deleted users, whose username and email stay reserved
+	UsernameExists(ctx context.Context, username string) (bool, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	GetByIDWithEmployeeDetail(ctx context.Context, id uint) (*model.User, error)
 	Update(ctx context.Context, user *model.User) error
+	// Delete soft-deletes the user; attendance and leave history are kept
 	Delete(ctx context.Context, id uint) error
+	// Restore undoes a soft delete, reporting false when no deleted user has this ID
+	Restore(ctx context.Context, id uint) (bool, error)
+	SetActive(ctx context.Context, id uint, active bool) error
+	GetAll(ctx context.Context) ([]model.User, error)
+	// GetActive returns the users that are neither deactivated nor deleted
+	GetActive(ctx context.Context) ([]model.User, error)
+	// List returns one page of users matching the filter and the number of matching users
+	List(ctx context.Context, filter UserFilter) ([]model.User, int64, error)
+	GetByDepartment(ctx context.Context, departmentID uint) ([]model.User, error)
+	// CountByDepartmentWithDeleted also counts deleted users, who keep their department when restored
+	CountByDepartmentWithDeleted(ctx context.Context, departmentID uint) (int64, error)
+	// UpdateDepartment also moves deleted users
+	UpdateDepartment(ctx context.Context, userID uint, departmentID *uint) error
+	// UpdateTOTP stores the two-factor secret and whether it is enabled
+	UpdateTOTP(ctx context.Context, userID uint, secret string, enabled bool) error
+	// AdvanceTOTPStep records a used time step, reporting false when that step or a later one was already used
+	AdvanceTOTPStep(ctx context.Context, userID uint, step int64) (bool, error)
 }
 
 type userRepository struct {
@@ -37,6 +88,15 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*m
 	return &user, nil
 }
 
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) GetByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
 	err := r.db.WithContext(ctx).First(&user, id).Error
@@ -46,10 +106,146 @@ func (r *userRepository) GetByID(ctx context.Context, id uint) (*model.User, err
 	return &user, nil
 }
 
+func (r *userRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Unscoped().First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) GetByIDWithEmployeeDetail(ctx context.Context, id uint) (*model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Preload("EmployeeDetail").First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) Update(ctx context.Context, user *model.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return r.db.WithContext(ctx).Omit("EmployeeDetail").Save(user).Error
+}
+
+func (r *userRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.User{}).Where("username = ?", username).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *userRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.User{}).Where("email = ?", email).Count(&count).Error
+	return count > 0, err
 }
 
 func (r *userRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&model.User{}, id).Error
 }
+
+func (r *userRepository) Restore(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&model.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *userRepository) SetActive(ctx context.Context, id uint, active bool) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("active", active).Error
+}
+
+func (r *userRepository) GetAll(ctx context.Context) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).Order("id").Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) GetActive(ctx context.Context) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).Where("active = ?", true).Order("id").Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) List(ctx context.Context, filter UserFilter) ([]model.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.User{})
+	switch filter.Status {
+	case UserStatusActive:
+		query = query.Where("active = ?", true)
+	case UserStatusInactive:
+		query = query.Where("active = ?", false)
+	case UserStatusDeleted:
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	if filter.Search != "" {
+		pattern := "%" + escapeLike(filter.Search) + "%"
+		query = query.Where("full_name LIKE ? OR email LIKE ? OR username LIKE ?", pattern, pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.DepartmentID != nil {
+		query = query.Where("department_id = ?", *filter.DepartmentID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sort := "id"
+	for _, field := range UserSortFields {
+		if field == filter.Sort {
+			sort = field
+		}
+	}
+	if filter.Descending {
+		sort += " DESC"
+	}
+	// Tie-break on id so pages stay stable when the sort column has duplicates
+	if !strings.HasPrefix(sort, "id") {
+		sort += ", id"
+	}
+
+	var users []model.User
+	err := query.Order(sort).Offset((filter.Page - 1) * filter.PageSize).Limit(filter.PageSize).Find(&users).Error
+	return users, total, err
+}
+
+// escapeLike makes % and _ in user input match literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func (r *userRepository) GetByDepartment(ctx context.Context, departmentID uint) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).Where("department_id = ?", departmentID).Order("full_name").Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) CountByDepartmentWithDeleted(ctx context.Context, departmentID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.User{}).Where("department_id = ?", departmentID).Count(&count).Error
+	return count, err
+}
+
+func (r *userRepository) UpdateDepartment(ctx context.Context, userID uint, departmentID *uint) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&model.User{}).Where("id = ?", userID).Update("department_id", departmentID).Error
+}
+
+func (r *userRepository) UpdateTOTP(ctx context.Context, userID uint, secret string, enabled bool) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"totp_secret":  secret,
+		"totp_enabled": enabled,
+	}).Error
+}
+
+func (r *userRepository) AdvanceTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
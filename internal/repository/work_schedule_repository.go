@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type WorkScheduleRepository interface {
+	Create(ctx context.Context, schedule *model.WorkSchedule) error
+	GetByDepartmentAndDay(ctx context.Context, departmentID uint, dayOfWeek int) (*model.WorkSchedule, error)
+	GetByDepartment(ctx context.Context, departmentID uint) ([]model.WorkSchedule, error)
+	Update(ctx context.Context, schedule *model.WorkSchedule) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type workScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkScheduleRepository(db *gorm.DB) WorkScheduleRepository {
+	return &workScheduleRepository{db: db}
+}
+
+func (r *workScheduleRepository) Create(ctx context.Context, schedule *model.WorkSchedule) error {
+	return r.db.WithContext(ctx).Create(schedule).Error
+}
+
+func (r *workScheduleRepository) GetByDepartmentAndDay(ctx context.Context, departmentID uint, dayOfWeek int) (*model.WorkSchedule, error) {
+	var schedule model.WorkSchedule
+	err := r.db.WithContext(ctx).
+		Where("department_id = ? AND day_of_week = ?", departmentID, dayOfWeek).
+		First(&schedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *workScheduleRepository) GetByDepartment(ctx context.Context, departmentID uint) ([]model.WorkSchedule, error) {
+	var schedules []model.WorkSchedule
+	err := r.db.WithContext(ctx).
+		Where("department_id = ?", departmentID).
+		Order("day_of_week").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *workScheduleRepository) Update(ctx context.Context, schedule *model.WorkSchedule) error {
+	return r.db.WithContext(ctx).Save(schedule).Error
+}
+
+func (r *workScheduleRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.WorkSchedule{}, id).Error
+}
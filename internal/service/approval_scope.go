@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+)
+
+// ApprovalScope limits whose requests, such as leave requests or attendance corrections, a user may see
+// or decide on
+type ApprovalScope int
+
+const (
+	// ApprovalScopeOwn only covers the user's own requests
+	ApprovalScopeOwn ApprovalScope = iota
+	// ApprovalScopeDepartment covers requests of members of the user's department
+	ApprovalScopeDepartment
+	// ApprovalScopeAll covers every request
+	ApprovalScopeAll
+)
+
+// ResolveApprovalScope picks the widest scope the granted permissions allow: allPermission covers every
+// request, departmentPermission those of the user's department
+func ResolveApprovalScope(granted []string, allPermission, departmentPermission string) ApprovalScope {
+	switch {
+	case slices.Contains(granted, model.PermissionAll), slices.Contains(granted, allPermission):
+		return ApprovalScopeAll
+	case slices.Contains(granted, departmentPermission):
+		return ApprovalScopeDepartment
+	default:
+		return ApprovalScopeOwn
+	}
+}
+
+// checkApprovalScope verifies that the actor may act on a request of the owner within the given scope. It
+// returns forbidden for someone else's request in ApprovalScopeOwn and outOfScope for a request from
+// outside the actor's department in ApprovalScopeDepartment.
+func checkApprovalScope(ctx context.Context, userRepo repository.UserRepository, scope ApprovalScope, actorID, ownerID uint, forbidden, outOfScope error) error {
+	switch scope {
+	case ApprovalScopeAll:
+		return nil
+	case ApprovalScopeDepartment:
+		actor, err := userRepo.GetByID(ctx, actorID)
+		if err != nil {
+			return ErrUserNotFound
+		}
+		owner, err := userRepo.GetByID(ctx, ownerID)
+		if err != nil {
+			return ErrUserNotFound
+		}
+		if actor.DepartmentID == nil || owner.DepartmentID == nil || *actor.DepartmentID != *owner.DepartmentID {
+			return outOfScope
+		}
+		return nil
+	default:
+		if ownerID != actorID {
+			return forbidden
+		}
+		return nil
+	}
+}
+
+// approvalDepartment returns the department whose requests a viewer sees in ApprovalScopeDepartment, or
+// nil when the viewer has none and only sees their own
+func approvalDepartment(ctx context.Context, userRepo repository.UserRepository, viewerID uint) (*uint, error) {
+	viewer, err := userRepo.GetByID(ctx, viewerID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return viewer.DepartmentID, nil
+}
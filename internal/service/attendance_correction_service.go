@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+)
+
+var (
+	ErrCorrectionNotFound     = errors.New("attendance correction not found")
+	ErrCorrectionEmpty        = errors.New("a correction must propose a check-in or check-out time")
+	ErrCorrectionPending      = errors.New("attendance already has a pending correction")
+	ErrCorrectionNotPending   = errors.New("attendance correction is no longer pending")
+	ErrCorrectionSelfApproval = errors.New("cannot decide on your own attendance correction")
+	ErrCorrectionForbidden    = errors.New("attendance correction belongs to another user")
+	ErrCorrectionOutOfScope   = errors.New("attendance correction belongs to a user outside your department")
+)
+
+// AttendanceCorrectionService handles corrections of past attendance records
+type AttendanceCorrectionService interface {
+	Submit(ctx context.Context, correction *model.AttendanceCorrection) error
+	// Approve applies the proposed times to the attendance and reclassifies it against the schedule
+	Approve(ctx context.Context, id, approverID uint, scope ApprovalScope) (*model.AttendanceCorrection, error)
+	Reject(ctx context.Context, id, approverID uint, scope ApprovalScope) (*model.AttendanceCorrection, error)
+	// Cancel withdraws one of the user's own pending corrections
+	Cancel(ctx context.Context, id, userID uint) (*model.AttendanceCorrection, error)
+	// GetVisible returns a correction if it falls within the viewer's scope
+	GetVisible(ctx context.Context, id, viewerID uint, scope ApprovalScope) (*model.AttendanceCorrection, error)
+	// GetVisibleByStatus lists the corrections within the viewer's scope, optionally filtered by status
+	GetVisibleByStatus(ctx context.Context, viewerID uint, scope ApprovalScope, status string) ([]model.AttendanceCorrection, error)
+}
+
+type attendanceCorrectionService struct {
+	correctionRepo    repository.AttendanceCorrectionRepository
+	attendanceRepo    repository.AttendanceRepository
+	userRepo          repository.UserRepository
+	attendanceService AttendanceService
+}
+
+func NewAttendanceCorrectionService(
+	correctionRepo repository.AttendanceCorrectionRepository,
+	attendanceRepo repository.AttendanceRepository,
+	userRepo repository.UserRepository,
+	attendanceService AttendanceService,
+) AttendanceCorrectionService {
+	return &attendanceCorrectionService{
+		correctionRepo:    correctionRepo,
+		attendanceRepo:    attendanceRepo,
+		userRepo:          userRepo,
+		attendanceService: attendanceService,
+	}
+}
+
+func (s *attendanceCorrectionService) Submit(ctx context.Context, correction *model.AttendanceCorrection) error {
+	if correction.CheckIn == nil && correction.CheckOut == nil {
+		return ErrCorrectionEmpty
+	}
+
+	// Other users' records are reported as missing
+	attendance, err := s.attendanceRepo.GetByID(ctx, correction.AttendanceID)
+	if err != nil || attendance.UserID != correction.UserID {
+		return ErrAttendanceNotFound
+	}
+
+	pending, err := s.correctionRepo.HasPending(ctx, attendance.ID)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return ErrCorrectionPending
+	}
+
+	applyCorrection(attendance, correction)
+	if err := s.attendanceService.Validate(ctx, attendance); err != nil {
+		return err
+	}
+
+	correction.Status = model.CorrectionStatusPending
+	correction.DecidedBy = nil
+	correction.DecidedAt = nil
+	correction.OriginalCheckIn = nil
+	correction.OriginalCheckOut = nil
+
+	return s.correctionRepo.Create(ctx, correction)
+}
+
+func (s *attendanceCorrectionService) Approve(ctx context.Context, id, approverID uint, scope ApprovalScope) (*model.AttendanceCorrection, error) {
+	correction, err := s.getDecidable(ctx, id, approverID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	attendance, err := s.attendanceRepo.GetByID(ctx, correction.AttendanceID)
+	if err != nil {
+		return nil, ErrAttendanceNotFound
+	}
+
+	// Keep the replaced times on the correction, then check the result again since the
+	// attendance may have changed since the correction was submitted
+	correction.OriginalCheckIn = attendance.CheckIn
+	correction.OriginalCheckOut = attendance.CheckOut
+	applyCorrection(attendance, correction)
+	if err := s.attendanceService.Validate(ctx, attendance); err != nil {
+		return nil, err
+	}
+	if err := s.attendanceService.Classify(ctx, attendance); err != nil {
+		return nil, err
+	}
+
+	correction, err = s.decide(ctx, correction, approverID, model.CorrectionStatusApproved, attendance)
+	if err != nil {
+		return nil, err
+	}
+
+	// Another session of the day may have become the first or last one
+	if err := s.attendanceService.ReclassifyDay(ctx, attendance.UserID, attendance.Date); err != nil {
+		log.Printf("Failed to reclassify attendance of user %d on %s: %v", attendance.UserID, attendance.Date.Format("2006-01-02"), err)
+	}
+	return correction, nil
+}
+
+func (s *attendanceCorrectionService) Reject(ctx context.Context, id, approverID uint, scope ApprovalScope) (*model.AttendanceCorrection, error) {
+	correction, err := s.getDecidable(ctx, id, approverID, scope)
+	if err != nil {
+		return nil, err
+	}
+	return s.decide(ctx, correction, approverID, model.CorrectionStatusRejected, nil)
+}
+
+func (s *attendanceCorrectionService) Cancel(ctx context.Context, id, userID uint) (*model.AttendanceCorrection, error) {
+	correction, err := s.correctionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrCorrectionNotFound
+	}
+	if correction.UserID != userID {
+		return nil, ErrCorrectionForbidden
+	}
+	if correction.Status != model.CorrectionStatusPending {
+		return nil, ErrCorrectionNotPending
+	}
+
+	correction.Status = model.CorrectionStatusCancelled
+	decided, err := s.correctionRepo.Decide(ctx, correction, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !decided {
+		return nil, ErrCorrectionNotPending
+	}
+	return correction, nil
+}
+
+func (s *attendanceCorrectionService) GetVisible(ctx context.Context, id, viewerID uint, scope ApprovalScope) (*model.AttendanceCorrection, error) {
+	correction, err := s.correctionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrCorrectionNotFound
+	}
+
+	// Own corrections are always visible, whatever the scope
+	if correction.UserID != viewerID {
+		if err := s.checkScope(ctx, correction, viewerID, scope); err != nil {
+			return nil, err
+		}
+	}
+	return correction, nil
+}
+
+func (s *attendanceCorrectionService) GetVisibleByStatus(ctx context.Context, viewerID uint, scope ApprovalScope, status string) ([]model.AttendanceCorrection, error) {
+	filter := repository.AttendanceCorrectionFilter{Status: status}
+	switch scope {
+	case ApprovalScopeAll:
+	case ApprovalScopeDepartment:
+		departmentID, err := approvalDepartment(ctx, s.userRepo, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if departmentID != nil {
+			filter.DepartmentID = departmentID
+		} else {
+			filter.UserID = &viewerID
+		}
+	default:
+		filter.UserID = &viewerID
+	}
+	return s.correctionRepo.Find(ctx, filter)
+}
+
+// getDecidable loads a pending correction the approver may decide on
+func (s *attendanceCorrectionService) getDecidable(ctx context.Context, id, approverID uint, scope ApprovalScope) (*model.AttendanceCorrection, error) {
+	correction, err := s.correctionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrCorrectionNotFound
+	}
+
+	if err := s.checkScope(ctx, correction, approverID, scope); err != nil {
+		return nil, err
+	}
+	if correction.Status != model.CorrectionStatusPending {
+		return nil, ErrCorrectionNotPending
+	}
+	if correction.UserID == approverID {
+		return nil, ErrCorrectionSelfApproval
+	}
+	return correction, nil
+}
+
+// decide stores the final state of a correction together with the corrected attendance, if any
+func (s *attendanceCorrectionService) decide(ctx context.Context, correction *model.AttendanceCorrection, approverID uint, status string, attendance *model.Attendance) (*model.AttendanceCorrection, error) {
+	now := time.Now()
+	correction.Status = status
+	correction.DecidedBy = &approverID
+	correction.DecidedAt = &now
+
+	decided, err := s.correctionRepo.Decide(ctx, correction, attendance)
+	if err != nil {
+		return nil, err
+	}
+	if !decided {
+		return nil, ErrCorrectionNotPending
+	}
+	return correction, nil
+}
+
+// checkScope verifies that the actor may act on the correction within the given scope
+func (s *attendanceCorrectionService) checkScope(ctx context.Context, correction *model.AttendanceCorrection, actorID uint, scope ApprovalScope) error {
+	return checkApprovalScope(ctx, s.userRepo, scope, actorID, correction.UserID, ErrCorrectionForbidden, ErrCorrectionOutOfScope)
+}
+
+// applyCorrection replaces the attendance times the correction proposes to change
+func applyCorrection(attendance *model.Attendance, correction *model.AttendanceCorrection) {
+	if correction.CheckIn != nil {
+		checkIn := *correction.CheckIn
+		attendance.CheckIn = &checkIn
+	}
+	if correction.CheckOut != nil {
+		checkOut := *correction.CheckOut
+		attendance.CheckOut = &checkOut
+	}
+}
@@ -5,60 +5,911 @@ import (
 	"absence/internal/repository"
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAttendanceNotFound     = errors.New("attendance not found")
+	ErrCheckOutBeforeCheckIn  = errors.New("check-out must be after check-in")
+	ErrCheckOutWithoutCheckIn = errors.New("check-out requires a check-in")
+	ErrCheckInOutsideWorkday  = errors.New("check-in must be on the day of the attendance or the day after")
+	ErrAttendanceInFuture     = errors.New("attendance times cannot be in the future")
+	ErrAttendanceExists       = errors.New("user already has an attendance record for this day")
+	ErrAttendanceOverlap      = errors.New("attendance overlaps another check-in session of the user")
+	ErrAttendanceNotDeleted   = errors.New("attendance is not deleted")
+	ErrAlreadyCheckedIn       = errors.New("already checked in")
+	ErrNoOpenCheckIn          = errors.New("no open check-in found")
+	ErrBreakInProgress        = errors.New("already on a break")
+	ErrNoOpenBreak            = errors.New("no running break found")
+	ErrInvalidMissingStatus   = errors.New("a day without check-in must have status absent or leave")
+	ErrNoUsersSelected        = errors.New("no users selected")
 )
 
+// AttendanceService records attendance as check-in/check-out sessions. A workday can have several
+// sessions, e.g. a split shift, and a session belongs to the day it was checked in on even when it
+// ends on the next one. Only the first session of a day can be late and only the last one can leave early.
 type AttendanceService interface {
-	CheckIn(ctx context.Context, userID uint, location string) error
-	CheckOut(ctx context.Context, userID uint, location string) error
+	// CheckIn starts a new session unless the user already has an open one
+	CheckIn(ctx context.Context, cmd AttendanceCommand) (*model.Attendance, error)
+	// CheckOut ends the user's open session, whichever day it started on, and a break still running
+	CheckOut(ctx context.Context, cmd AttendanceCommand) (*model.Attendance, error)
+	// StartBreak starts a break in the user's open session
+	StartBreak(ctx context.Context, userID uint) (*model.Attendance, error)
+	// EndBreak ends the running break of the user's open session
+	EndBreak(ctx context.Context, userID uint) (*model.Attendance, error)
+	// GetAttendanceByID returns a record with its regular and overtime minutes
 	GetAttendanceByID(ctx context.Context, id uint) (*model.Attendance, error)
-	GetUserAttendances(ctx context.Context, userID uint, startDate, endDate time.Time) ([]model.Attendance, error)
+	// GetUserAttendances returns the user's records of a period with their regular and overtime
+	// minutes, and the totals of the period
+	GetUserAttendances(ctx context.Context, userID uint, startDate, endDate time.Time) ([]model.Attendance, WorkedHours, error)
+	// Validate checks the times of an edited attendance, including that it does not overlap another session
+	Validate(ctx context.Context, attendance *model.Attendance) error
+	// Classify recomputes the schedule classification and status of an attendance whose times were edited
+	Classify(ctx context.Context, attendance *model.Attendance) error
+	// ReclassifyDay updates the classification of every session of a user's workday, e.g. after one of
+	// them was edited and another became the first or last session of the day
+	ReclassifyDay(ctx context.Context, userID uint, day time.Time) error
+	// CreateManual records a day for any user as entered by an administrator
+	CreateManual(ctx context.Context, userID uint, day time.Time, edit AttendanceEdit) (*model.Attendance, error)
+	// Edit replaces the times of an attendance record
+	Edit(ctx context.Context, id uint, edit AttendanceEdit) (*model.Attendance, error)
+	Delete(ctx context.Context, id uint) error
+	// Restore brings back a deleted record unless the day has been filled in again since
+	Restore(ctx context.Context, id uint) (*model.Attendance, error)
+	// BulkEdit applies the same edit to the selected users' day where nobody checked in yet, creating
+	// missing records or replacing an absent or leave day; users who checked in are skipped
+	BulkEdit(ctx context.Context, selection AttendanceSelection, day time.Time, edit AttendanceEdit) (BulkEditResult, error)
+	// MarkMissingDay records a leave or absent day for a user who never checked in.
+	// It returns nil without creating anything when the day already has a record,
+	// is a holiday, or is not a scheduled workday for the user.
+	MarkMissingDay(ctx context.Context, userID uint, day time.Time) (*model.Attendance, error)
+	// MarkMissingDays runs MarkMissingDay for every user and returns how many records were created
+	MarkMissingDays(ctx context.Context, day time.Time) (int, error)
+}
+
+// AttendanceCommand carries everything a client reports when checking in or out
+type AttendanceCommand struct {
+	UserID   uint
+	Location string
+	// Point is nil when the client did not send coordinates
+	Point  *GeoPoint
+	Notes  string
+	Device string
+	// Source is one of the model.AttendanceSource* values; empty means web
+	Source string
+}
+
+func (c AttendanceCommand) source() string {
+	if c.Source == "" {
+		return model.AttendanceSourceWeb
+	}
+	return c.Source
+}
+
+// AttendanceEdit is an administrator's change to an attendance record. The status of a day with a
+// check-in follows from the schedule; Status only applies to days without one and must then be
+// absent or leave.
+type AttendanceEdit struct {
+	CheckIn *time.Time
+	// CheckOut is nil while the user is still checked in
+	CheckOut *time.Time
+	Status   string
+}
+
+// apply replaces the times of the attendance with the edited ones
+func (e AttendanceEdit) apply(attendance *model.Attendance) {
+	attendance.CheckIn = e.CheckIn
+	attendance.CheckOut = e.CheckOut
+	if e.CheckIn == nil {
+		attendance.Status = e.Status
+	} else {
+		attendance.SourceIn = model.AttendanceSourceManual
+	}
+	if e.CheckOut != nil {
+		attendance.SourceOut = model.AttendanceSourceManual
+	}
+}
+
+// validate checks the edit against the workday it is applied to
+func (e AttendanceEdit) validate(day time.Time, now time.Time) error {
+	if e.CheckIn == nil && e.Status != model.AttendanceStatusAbsent && e.Status != model.AttendanceStatusLeave {
+		return ErrInvalidMissingStatus
+	}
+	attendance := &model.Attendance{Date: startOfDay(day)}
+	e.apply(attendance)
+	return validateAttendanceTimes(attendance, now)
+}
+
+// AttendanceSelection picks the users a bulk edit applies to; both fields may be combined
+type AttendanceSelection struct {
+	UserIDs []uint
+	// DepartmentID selects the active members of the department
+	DepartmentID *uint
+}
+
+// BulkEditResult reports what a bulk edit did to the selected users
+type BulkEditResult struct {
+	Updated int
+	// Skipped are the users whose day already has a check-in; bulk edits never overwrite recorded times
+	Skipped []uint
+}
+
+// AttendanceConfig holds the deployment-specific attendance rules
+type AttendanceConfig struct {
+	HolidayPolicy  HolidayPolicy
+	GeofencePolicy GeofencePolicy
+	// AbsenceCutoff is the time after midnight at which users without a
+	// check-in are marked absent for that day
+	AbsenceCutoff time.Duration
+	// MaxSessionDuration is how long a session can stay open; older ones are treated as a
+	// forgotten check-out that needs a correction and no longer block checking in
+	MaxSessionDuration time.Duration
+	Breaks             BreakRules
+	Overtime           OvertimeRules
+}
+
+// BreakRules are checked per session and only flagged on the attendance, never enforced
+type BreakRules struct {
+	// MaxMinutes is the longest total break of a session; 0 disables the rule
+	MaxMinutes int
+	// Sessions with more than RequiredAfter of net work need breaks of at least RequiredMinutes;
+	// 0 disables the rule
+	RequiredAfter   time.Duration
+	RequiredMinutes int
 }
 
 type attendanceService struct {
-	attendanceRepo repository.AttendanceRepository
+	attendanceRepo  repository.AttendanceRepository
+	breakRepo       repository.AttendanceBreakRepository
+	userRepo        repository.UserRepository
+	scheduleService WorkScheduleService
+	holidayService  HolidayService
+	leaveService    LeaveService
+	geofenceService GeofenceService
+	config          *AttendanceConfig
 }
 
-func NewAttendanceService(attendanceRepo repository.AttendanceRepository) AttendanceService {
-	return &attendanceService{attendanceRepo: attendanceRepo}
+func NewAttendanceService(
+	attendanceRepo repository.AttendanceRepository,
+	breakRepo repository.AttendanceBreakRepository,
+	userRepo repository.UserRepository,
+	scheduleService WorkScheduleService,
+	holidayService HolidayService,
+	leaveService LeaveService,
+	geofenceService GeofenceService,
+	config *AttendanceConfig,
+) AttendanceService {
+	return &attendanceService{
+		attendanceRepo:  attendanceRepo,
+		breakRepo:       breakRepo,
+		userRepo:        userRepo,
+		scheduleService: scheduleService,
+		holidayService:  holidayService,
+		leaveService:    leaveService,
+		geofenceService: geofenceService,
+		config:          config,
+	}
 }
 
-func (s *attendanceService) CheckIn(ctx context.Context, userID uint, location string) error {
+func (s *attendanceService) CheckIn(ctx context.Context, cmd AttendanceCommand) (*model.Attendance, error) {
 	now := time.Now()
 
-	// Check if already checked in today
-	existing, _ := s.attendanceRepo.GetByUserIDAndDate(ctx, userID, now)
-	if existing != nil {
-		return errors.New("already checked in today")
+	open, err := s.openSession(ctx, cmd.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, ErrAlreadyCheckedIn
 	}
 
-	attendance := &model.Attendance{
-		UserID:   userID,
-		CheckIn:  now,
-		Location: location,
+	day, err := s.workday(ctx, cmd.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	holiday, err := s.holidayService.GetHoliday(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+	if holiday != nil && s.config.HolidayPolicy == HolidayPolicyReject {
+		return nil, ErrCheckInOnHoliday
+	}
+
+	geofence, err := s.geofenceService.Verify(ctx, cmd.Point)
+	if err != nil {
+		return nil, err
+	}
+
+	// A day the system pre-marked as absent or leave has no check-in yet and becomes the first session
+	sessions, err := s.attendanceRepo.FindByUserIDAndDate(ctx, cmd.UserID, day)
+	if err != nil {
+		return nil, err
+	}
+	attendance := &model.Attendance{UserID: cmd.UserID}
+	if len(sessions) > 0 && sessions[0].CheckIn == nil {
+		attendance = &sessions[0]
+	}
+	attendance.Date = day
+	attendance.CheckIn = &now
+	attendance.LocationIn = cmd.Location
+	attendance.LatitudeIn, attendance.LongitudeIn = cmd.Point.coordinates()
+	attendance.GeofenceInID = geofence.GeofenceID
+	attendance.RemoteCheckIn = geofence.Remote
+	attendance.NotesIn = cmd.Notes
+	attendance.DeviceIn = cmd.Device
+	attendance.SourceIn = cmd.source()
+	if err := s.classify(ctx, attendance, sessions); err != nil {
+		return nil, err
+	}
+
+	if attendance.ID != 0 {
+		err = s.attendanceRepo.Update(ctx, attendance)
+	} else {
+		err = s.attendanceRepo.Create(ctx, attendance)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The previous session of the day is no longer the last one, so it did not leave early
+	s.reclassifyDay(ctx, attendance)
+	return attendance, nil
+}
+
+func (s *attendanceService) CheckOut(ctx context.Context, cmd AttendanceCommand) (*model.Attendance, error) {
+	now := time.Now()
+
+	attendance, err := s.openSession(ctx, cmd.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	if attendance == nil {
+		return nil, ErrNoOpenCheckIn
+	}
+
+	geofence, err := s.geofenceService.Verify(ctx, cmd.Point)
+	if err != nil {
+		return nil, err
+	}
+
+	// A break still running ends with the session
+	if _, err := s.endBreak(ctx, attendance.ID, now); err != nil {
+		return nil, err
+	}
+
+	// Check-out details are stored separately so the check-in ones are kept
+	attendance.CheckOut = &now
+	attendance.LocationOut = cmd.Location
+	attendance.LatitudeOut, attendance.LongitudeOut = cmd.Point.coordinates()
+	attendance.GeofenceOutID = geofence.GeofenceID
+	attendance.RemoteCheckOut = geofence.Remote
+	attendance.NotesOut = cmd.Notes
+	attendance.DeviceOut = cmd.Device
+	attendance.SourceOut = cmd.source()
+
+	// Classify against the schedule of the workday the session belongs to
+	if err := s.Classify(ctx, attendance); err != nil {
+		return nil, err
 	}
 
-	return s.attendanceRepo.Create(ctx, attendance)
+	if err := s.attendanceRepo.Update(ctx, attendance); err != nil {
+		return nil, err
+	}
+	return attendance, nil
 }
 
-func (s *attendanceService) CheckOut(ctx context.Context, userID uint, location string) error {
+func (s *attendanceService) StartBreak(ctx context.Context, userID uint) (*model.Attendance, error) {
 	now := time.Now()
 
-	attendance, err := s.attendanceRepo.GetByUserIDAndDate(ctx, userID, now)
+	attendance, err := s.openSession(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if attendance == nil {
+		return nil, ErrNoOpenCheckIn
+	}
+	running, err := s.openBreak(ctx, attendance.ID)
 	if err != nil {
-		return errors.New("no check-in record found for today")
+		return nil, err
 	}
+	if running != nil {
+		return nil, ErrBreakInProgress
+	}
+
+	attendanceBreak := model.AttendanceBreak{AttendanceID: attendance.ID, StartedAt: now}
+	if err := s.breakRepo.Create(ctx, &attendanceBreak); err != nil {
+		return nil, err
+	}
+	attendance.Breaks = append(attendance.Breaks, attendanceBreak)
+	return attendance, nil
+}
 
-	attendance.CheckOut = now
-	attendance.Location = location
+func (s *attendanceService) EndBreak(ctx context.Context, userID uint) (*model.Attendance, error) {
+	now := time.Now()
+
+	attendance, err := s.openSession(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if attendance == nil {
+		return nil, ErrNoOpenCheckIn
+	}
+	ended, err := s.endBreak(ctx, attendance.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ended {
+		return nil, ErrNoOpenBreak
+	}
 
-	return s.attendanceRepo.Update(ctx, attendance)
+	// Update the break time of the session
+	if err := s.Classify(ctx, attendance); err != nil {
+		return nil, err
+	}
+	if err := s.attendanceRepo.Update(ctx, attendance); err != nil {
+		return nil, err
+	}
+	return attendance, nil
+}
+
+// openBreak returns the running break of a session, or nil when there is none
+func (s *attendanceService) openBreak(ctx context.Context, attendanceID uint) (*model.AttendanceBreak, error) {
+	attendanceBreak, err := s.breakRepo.GetOpen(ctx, attendanceID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return attendanceBreak, err
+}
+
+// endBreak ends the running break of a session at now, reporting false when there was none
+func (s *attendanceService) endBreak(ctx context.Context, attendanceID uint, now time.Time) (bool, error) {
+	running, err := s.openBreak(ctx, attendanceID)
+	if err != nil || running == nil {
+		return false, err
+	}
+	running.EndedAt = &now
+	if err := s.breakRepo.Update(ctx, running); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// workday returns the day a check-in at now belongs to: the previous day while its overnight shift is
+// still running, e.g. after a break past midnight, and the calendar day otherwise
+func (s *attendanceService) workday(ctx context.Context, userID uint, now time.Time) (time.Time, error) {
+	yesterday := startOfDay(now).AddDate(0, 0, -1)
+	schedule, err := s.scheduleService.GetUserSchedule(ctx, userID, yesterday)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if schedule != nil {
+		_, end, err := scheduleWindow(schedule, yesterday)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if now.Before(end) {
+			return yesterday, nil
+		}
+	}
+	return startOfDay(now), nil
+}
+
+// openSession returns the user's open session, or nil when there is none that started recently
+// enough to still be running
+func (s *attendanceService) openSession(ctx context.Context, userID uint, now time.Time) (*model.Attendance, error) {
+	attendance, err := s.attendanceRepo.GetOpenSession(ctx, userID, now.Add(-s.config.MaxSessionDuration))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return attendance, err
 }
 
 func (s *attendanceService) GetAttendanceByID(ctx context.Context, id uint) (*model.Attendance, error) {
+	attendance, err := s.attendanceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The weekly threshold depends on the sessions before this one in the same week
+	sessions, err := s.attendanceRepo.GetUserAttendances(ctx, attendance.UserID, startOfWeek(attendance.Date), attendance.Date.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	calculateHours(s.config.Overtime, sessions)
+	for i := range sessions {
+		if sessions[i].ID == attendance.ID {
+			return &sessions[i], nil
+		}
+	}
+	return attendance, nil
+}
+
+func (s *attendanceService) GetUserAttendances(ctx context.Context, userID uint, startDate, endDate time.Time) ([]model.Attendance, WorkedHours, error) {
+	// Start on Monday so that the days before startDate count towards the weekly threshold
+	sessions, err := s.attendanceRepo.GetUserAttendances(ctx, userID, startOfWeek(startDate), endDate)
+	if err != nil {
+		return nil, WorkedHours{}, err
+	}
+	calculateHours(s.config.Overtime, sessions)
+
+	first := 0
+	for first < len(sessions) && sessions[first].Date.Before(startDate) {
+		first++
+	}
+	sessions = sessions[first:]
+	return sessions, sumHours(sessions), nil
+}
+
+func (s *attendanceService) Validate(ctx context.Context, attendance *model.Attendance) error {
+	now := time.Now()
+	if err := validateAttendanceTimes(attendance, now); err != nil {
+		return err
+	}
+	if attendance.CheckIn == nil {
+		return nil
+	}
+
+	// A session without check-out runs until now
+	end := now
+	if attendance.CheckOut != nil {
+		end = *attendance.CheckOut
+	}
+	overlapping, err := s.attendanceRepo.FindOverlapping(ctx, attendance.UserID, attendance.ID, *attendance.CheckIn, end, now.Add(-s.config.MaxSessionDuration))
+	if err != nil {
+		return err
+	}
+	if len(overlapping) > 0 {
+		return ErrAttendanceOverlap
+	}
+	return nil
+}
+
+func (s *attendanceService) Classify(ctx context.Context, attendance *model.Attendance) error {
+	sessions, err := s.attendanceRepo.FindByUserIDAndDate(ctx, attendance.UserID, attendance.Date)
+	if err != nil {
+		return err
+	}
+	return s.classify(ctx, attendance, sessions)
+}
+
+func (s *attendanceService) ReclassifyDay(ctx context.Context, userID uint, day time.Time) error {
+	sessions, err := s.attendanceRepo.FindByUserIDAndDate(ctx, userID, day)
+	if err != nil {
+		return err
+	}
+
+	for i := range sessions {
+		session := sessions[i]
+		if err := s.classify(ctx, &session, sessions); err != nil {
+			return err
+		}
+		if sameClassification(&session, &sessions[i]) {
+			continue
+		}
+		if err := s.attendanceRepo.Update(ctx, &session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// classify sets the schedule classification, status and break time of a session given the sessions
+// recorded for its workday, which may include an older version of the session itself
+func (s *attendanceService) classify(ctx context.Context, attendance *model.Attendance, sessions []model.Attendance) error {
+	attendance.CheckInStatus, attendance.LateMinutes = "", 0
+	attendance.CheckOutStatus, attendance.EarlyLeaveMinutes = "", 0
+	attendance.HolidayOvertime = false
+	attendance.BreakMinutes, attendance.WorkedMinutes = 0, 0
+	attendance.BreakExceeded, attendance.BreakMissing = false, false
+	if attendance.CheckIn == nil {
+		return nil
+	}
+
+	first, last := true, true
+	for _, other := range sessions {
+		if other.ID == attendance.ID || other.CheckIn == nil {
+			continue
+		}
+		if other.CheckIn.Before(*attendance.CheckIn) {
+			first = false
+		} else {
+			last = false
+		}
+	}
+
+	holiday, err := s.holidayService.GetHoliday(ctx, attendance.Date)
+	if err != nil {
+		return err
+	}
+	// Holidays have no working hours, so holiday overtime is never late
+	var schedule *model.WorkSchedule
+	if holiday == nil {
+		schedule, err = s.scheduleService.GetUserSchedule(ctx, attendance.UserID, attendance.Date)
+		if err != nil {
+			return err
+		}
+	}
+
+	attendance.HolidayOvertime = holiday != nil
+	attendance.CheckInStatus = unclassified(schedule)
+	if first {
+		attendance.CheckInStatus, attendance.LateMinutes, err = classifyCheckIn(schedule, attendance.Date, *attendance.CheckIn)
+		if err != nil {
+			return err
+		}
+	}
+	if attendance.CheckOut != nil {
+		attendance.CheckOutStatus = unclassified(schedule)
+		if last {
+			attendance.CheckOutStatus, attendance.EarlyLeaveMinutes, err = classifyCheckOut(schedule, attendance.Date, *attendance.CheckOut)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	attendance.Status = attendanceStatus(attendance)
+	return s.measure(ctx, attendance)
+}
+
+// measure adds up the breaks within a session, its net worked time and the break rules it breaks
+func (s *attendanceService) measure(ctx context.Context, attendance *model.Attendance) error {
+	if attendance.ID != 0 {
+		breaks, err := s.breakRepo.FindByAttendanceID(ctx, attendance.ID)
+		if err != nil {
+			return err
+		}
+		attendance.Breaks = breaks
+	}
+
+	end := time.Now()
+	if attendance.CheckOut != nil {
+		end = *attendance.CheckOut
+	}
+	for _, attendanceBreak := range attendance.Breaks {
+		attendance.BreakMinutes += attendanceBreak.Minutes(*attendance.CheckIn, end)
+	}
+
+	rules := s.config.Breaks
+	attendance.BreakExceeded = rules.MaxMinutes > 0 && attendance.BreakMinutes > rules.MaxMinutes
+	if attendance.CheckOut == nil {
+		return nil
+	}
+	attendance.WorkedMinutes = int(attendance.CheckOut.Sub(*attendance.CheckIn)/time.Minute) - attendance.BreakMinutes
+	attendance.BreakMissing = rules.RequiredAfter > 0 &&
+		attendance.WorkedMinutes > int(rules.RequiredAfter/time.Minute) &&
+		attendance.BreakMinutes < rules.RequiredMinutes
+	return nil
+}
+
+// sameClassification reports whether classify derived the same values for both versions of a session
+func sameClassification(a, b *model.Attendance) bool {
+	return a.CheckInStatus == b.CheckInStatus &&
+		a.LateMinutes == b.LateMinutes &&
+		a.CheckOutStatus == b.CheckOutStatus &&
+		a.EarlyLeaveMinutes == b.EarlyLeaveMinutes &&
+		a.HolidayOvertime == b.HolidayOvertime &&
+		a.Status == b.Status &&
+		a.BreakMinutes == b.BreakMinutes &&
+		a.WorkedMinutes == b.WorkedMinutes &&
+		a.BreakExceeded == b.BreakExceeded &&
+		a.BreakMissing == b.BreakMissing
+}
+
+// reclassifyDay updates the other sessions of the attendance's workday after it was saved. Failures are
+// only logged since the attendance itself was stored.
+func (s *attendanceService) reclassifyDay(ctx context.Context, attendance *model.Attendance) {
+	if err := s.ReclassifyDay(ctx, attendance.UserID, attendance.Date); err != nil {
+		log.Printf("Failed to reclassify attendance of user %d on %s: %v", attendance.UserID, attendance.Date.Format("2006-01-02"), err)
+	}
+}
+
+func (s *attendanceService) CreateManual(ctx context.Context, userID uint, day time.Time, edit AttendanceEdit) (*model.Attendance, error) {
+	day = startOfDay(day)
+	if err := edit.validate(day, time.Now()); err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	sessions, err := s.attendanceRepo.FindByUserIDAndDate(ctx, userID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	// A session can be added to a day with sessions or fill in an absent or leave day, but an absent
+	// or leave day cannot be added to a day that has any record
+	attendance := &model.Attendance{UserID: userID, Date: day}
+	if len(sessions) > 0 {
+		if edit.CheckIn == nil {
+			return nil, ErrAttendanceExists
+		}
+		if sessions[0].CheckIn == nil {
+			attendance = &sessions[0]
+		}
+	}
+	if err := s.save(ctx, attendance, edit); err != nil {
+		return nil, err
+	}
+	return attendance, nil
+}
+
+func (s *attendanceService) Edit(ctx context.Context, id uint, edit AttendanceEdit) (*model.Attendance, error) {
+	attendance, err := s.attendanceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrAttendanceNotFound
+	}
+	if err := edit.validate(attendance.Date, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.save(ctx, attendance, edit); err != nil {
+		return nil, err
+	}
+	return attendance, nil
+}
+
+func (s *attendanceService) Delete(ctx context.Context, id uint) error {
+	attendance, err := s.attendanceRepo.GetByID(ctx, id)
+	if err != nil {
+		return ErrAttendanceNotFound
+	}
+	if err := s.attendanceRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// Another session may have become the first or last one of the day
+	s.reclassifyDay(ctx, attendance)
+	return nil
+}
+
+func (s *attendanceService) Restore(ctx context.Context, id uint) (*model.Attendance, error) {
+	attendance, err := s.attendanceRepo.GetByIDWithDeleted(ctx, id)
+	if err != nil {
+		return nil, ErrAttendanceNotFound
+	}
+	if !attendance.DeletedAt.Valid {
+		return nil, ErrAttendanceNotDeleted
+	}
+
+	if err := s.Validate(ctx, attendance); err != nil {
+		return nil, err
+	}
+	// An absent or leave day is the only record of the day
+	sessions, err := s.attendanceRepo.FindByUserIDAndDate(ctx, attendance.UserID, attendance.Date)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range sessions {
+		if attendance.CheckIn == nil || other.CheckIn == nil {
+			return nil, ErrAttendanceExists
+		}
+	}
+
+	restored, err := s.attendanceRepo.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !restored {
+		return nil, ErrAttendanceNotFound
+	}
+
+	// The restored session may be the first or last one of the day again
+	s.reclassifyDay(ctx, attendance)
 	return s.attendanceRepo.GetByID(ctx, id)
 }
 
-func (s *attendanceService) GetUserAttendances(ctx context.Context, userID uint, startDate, endDate time.Time) ([]model.Attendance, error) {
-	return s.attendanceRepo.GetUserAttendances(ctx, userID, startDate, endDate)
+func (s *attendanceService) BulkEdit(ctx context.Context, selection AttendanceSelection, day time.Time, edit AttendanceEdit) (BulkEditResult, error) {
+	day = startOfDay(day)
+	if err := edit.validate(day, time.Now()); err != nil {
+		return BulkEditResult{}, err
+	}
+
+	userIDs, err := s.selectUsers(ctx, selection)
+	if err != nil {
+		return BulkEditResult{}, err
+	}
+	if len(userIDs) == 0 {
+		return BulkEditResult{}, ErrNoUsersSelected
+	}
+
+	// Keep going past individual failures so one broken user does not block everyone else
+	result := BulkEditResult{Skipped: []uint{}}
+	var errs []error
+	for _, userID := range userIDs {
+		if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", userID, ErrUserNotFound))
+			continue
+		}
+
+		sessions, err := s.attendanceRepo.FindByUserIDAndDate(ctx, userID, day)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", userID, err))
+			continue
+		}
+		// Only an absent or leave day has a record without a check-in, and it is the only record of its day
+		attendance := &model.Attendance{UserID: userID, Date: day}
+		if len(sessions) > 0 {
+			if sessions[0].CheckIn != nil {
+				result.Skipped = append(result.Skipped, userID)
+				continue
+			}
+			attendance = &sessions[0]
+		}
+		if err := s.save(ctx, attendance, edit); err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", userID, err))
+			continue
+		}
+		result.Updated++
+	}
+	return result, errors.Join(errs...)
+}
+
+// selectUsers returns the distinct IDs of the selected users
+func (s *attendanceService) selectUsers(ctx context.Context, selection AttendanceSelection) ([]uint, error) {
+	userIDs := slices.Clone(selection.UserIDs)
+	if selection.DepartmentID != nil {
+		members, err := s.userRepo.GetByDepartment(ctx, *selection.DepartmentID)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if member.Active {
+				userIDs = append(userIDs, member.ID)
+			}
+		}
+	}
+
+	slices.Sort(userIDs)
+	return slices.Compact(userIDs), nil
+}
+
+// save applies a validated edit, checks it against the other records of the day, reclassifies the
+// record and stores it
+func (s *attendanceService) save(ctx context.Context, attendance *model.Attendance, edit AttendanceEdit) error {
+	edit.apply(attendance)
+	if err := s.Validate(ctx, attendance); err != nil {
+		return err
+	}
+	if attendance.CheckIn == nil {
+		// An absent or leave day is the only record of the day
+		sessions, err := s.attendanceRepo.FindByUserIDAndDate(ctx, attendance.UserID, attendance.Date)
+		if err != nil {
+			return err
+		}
+		for _, other := range sessions {
+			if other.ID != attendance.ID {
+				return ErrAttendanceExists
+			}
+		}
+	}
+	if err := s.Classify(ctx, attendance); err != nil {
+		return err
+	}
+
+	var err error
+	if attendance.ID == 0 {
+		err = s.attendanceRepo.Create(ctx, attendance)
+	} else {
+		err = s.attendanceRepo.Update(ctx, attendance)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.reclassifyDay(ctx, attendance)
+	return nil
+}
+
+func (s *attendanceService) MarkMissingDay(ctx context.Context, userID uint, day time.Time) (*model.Attendance, error) {
+	day = startOfDay(day)
+
+	sessions, err := s.attendanceRepo.FindByUserIDAndDate(ctx, userID, day)
+	if err != nil || len(sessions) > 0 {
+		return nil, err
+	}
+
+	holiday, err := s.holidayService.GetHoliday(ctx, day)
+	if err != nil || holiday != nil {
+		return nil, err
+	}
+
+	status := model.AttendanceStatusAbsent
+	leave, err := s.leaveService.GetApprovedLeave(ctx, userID, day)
+	if err != nil {
+		return nil, err
+	}
+	if leave != nil {
+		status = model.AttendanceStatusLeave
+	} else {
+		// Without working hours the day is not a workday, so nobody can be absent
+		schedule, err := s.scheduleService.GetUserSchedule(ctx, userID, day)
+		if err != nil || schedule == nil {
+			return nil, err
+		}
+	}
+
+	attendance := &model.Attendance{
+		UserID: userID,
+		Date:   day,
+		Status: status,
+	}
+	if err := s.attendanceRepo.Create(ctx, attendance); err != nil {
+		return nil, err
+	}
+	return attendance, nil
+}
+
+func (s *attendanceService) MarkMissingDays(ctx context.Context, day time.Time) (int, error) {
+	// Deactivated and deleted users are not expected at work
+	users, err := s.userRepo.GetActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Keep going past individual failures so one broken user does not block everyone else
+	marked := 0
+	var errs []error
+	for _, user := range users {
+		attendance, err := s.MarkMissingDay(ctx, user.ID, day)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", user.ID, err))
+			continue
+		}
+		if attendance != nil {
+			marked++
+		}
+	}
+	return marked, errors.Join(errs...)
+}
+
+// attendanceStatus derives the day status from the check-in classification.
+// Records without a check-in keep the absent or leave status the system gave them.
+func attendanceStatus(attendance *model.Attendance) string {
+	switch {
+	case attendance.CheckIn == nil:
+		return attendance.Status
+	case attendance.CheckInStatus == model.ScheduleLate:
+		return model.AttendanceStatusLate
+	default:
+		return model.AttendanceStatusPresent
+	}
+}
+
+// validateAttendanceTimes checks edited check-in and check-out times against the attendance's workday
+func validateAttendanceTimes(attendance *model.Attendance, now time.Time) error {
+	if attendance.CheckIn == nil {
+		if attendance.CheckOut != nil {
+			return ErrCheckOutWithoutCheckIn
+		}
+		return nil
+	}
+
+	// Sessions of a night shift can start after midnight
+	checkInDay := startOfDay(attendance.CheckIn.Local())
+	workday := startOfDay(attendance.Date)
+	if checkInDay.Before(workday) || checkInDay.After(workday.AddDate(0, 0, 1)) {
+		return ErrCheckInOutsideWorkday
+	}
+	if attendance.CheckIn.After(now) {
+		return ErrAttendanceInFuture
+	}
+	if attendance.CheckOut == nil {
+		return nil
+	}
+	if !attendance.CheckOut.After(*attendance.CheckIn) {
+		return ErrCheckOutBeforeCheckIn
+	}
+	if attendance.CheckOut.After(now) {
+		return ErrAttendanceInFuture
+	}
+	return nil
+}
+
+// startOfDay returns local midnight of the calendar day of t
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
 }
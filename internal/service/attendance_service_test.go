@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+)
+
+type attendanceRepositoryStub struct {
+	repository.AttendanceRepository
+	records map[uint]model.Attendance
+}
+
+func (r *attendanceRepositoryStub) FindByUserIDAndDate(ctx context.Context, userID uint, date time.Time) ([]model.Attendance, error) {
+	var sessions []model.Attendance
+	for _, record := range r.records {
+		if record.UserID == userID && record.Date.Equal(date) {
+			sessions = append(sessions, record)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *attendanceRepositoryStub) Create(ctx context.Context, attendance *model.Attendance) error {
+	attendance.ID = uint(len(r.records) + 1)
+	r.records[attendance.ID] = *attendance
+	return nil
+}
+
+func (r *attendanceRepositoryStub) Update(ctx context.Context, attendance *model.Attendance) error {
+	r.records[attendance.ID] = *attendance
+	return nil
+}
+
+func TestBulkEditSkipsRecordedCheckIns(t *testing.T) {
+	day := startOfDay(time.Now().AddDate(0, 0, -1))
+	checkIn := day.Add(9 * time.Hour)
+	checkOut := day.Add(17 * time.Hour)
+
+	attendanceRepo := &attendanceRepositoryStub{records: map[uint]model.Attendance{
+		1: {ID: 1, UserID: 2, Date: day, CheckIn: &checkIn, CheckOut: &checkOut, Status: model.AttendanceStatusPresent},
+		2: {ID: 2, UserID: 3, Date: day, Status: model.AttendanceStatusLeave},
+	}}
+	userRepo := &userRepositoryStub{users: map[uint]model.User{1: {ID: 1}, 2: {ID: 2}, 3: {ID: 3}}}
+	svc := NewAttendanceService(attendanceRepo, nil, userRepo, nil, nil, nil, nil, &AttendanceConfig{})
+
+	selection := AttendanceSelection{UserIDs: []uint{1, 2, 3}}
+	result, err := svc.BulkEdit(context.Background(), selection, day, AttendanceEdit{Status: model.AttendanceStatusAbsent})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Updated != 2 {
+		t.Errorf("expected 2 updated records, got %d", result.Updated)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != 2 {
+		t.Errorf("expected user 2 to be skipped, got %v", result.Skipped)
+	}
+
+	session := attendanceRepo.records[1]
+	if session.CheckIn == nil || !session.CheckIn.Equal(checkIn) || session.Status != model.AttendanceStatusPresent {
+		t.Errorf("expected the check-in of user 2 to be kept, got %+v", session)
+	}
+	if status := attendanceRepo.records[2].Status; status != model.AttendanceStatusAbsent {
+		t.Errorf("expected the leave day of user 3 to become absent, got %q", status)
+	}
+	created, err := attendanceRepo.FindByUserIDAndDate(context.Background(), 1, day)
+	if err != nil || len(created) != 1 || created[0].Status != model.AttendanceStatusAbsent {
+		t.Errorf("expected an absent day for user 1, got %+v", created)
+	}
+}
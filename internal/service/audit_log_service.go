@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+)
+
+type AuditLogService interface {
+	List(ctx context.Context, filter repository.AuditLogFilter) ([]model.AuditLog, int64, error)
+}
+
+type auditLogService struct {
+	auditLogRepo repository.AuditLogRepository
+}
+
+func NewAuditLogService(auditLogRepo repository.AuditLogRepository) AuditLogService {
+	return &auditLogService{auditLogRepo: auditLogRepo}
+}
+
+func (s *auditLogService) List(ctx context.Context, filter repository.AuditLogFilter) ([]model.AuditLog, int64, error) {
+	return s.auditLogRepo.List(ctx, filter)
+}
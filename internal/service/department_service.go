@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+)
+
+var (
+	ErrDepartmentNotFound = errors.New("department not found")
+	ErrDepartmentNotEmpty = errors.New("department still has members")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserNotInDept      = errors.New("user is not a member of this department")
+)
+
+type DepartmentService interface {
+	Create(ctx context.Context, department *model.Department) error
+	GetByID(ctx context.Context, id uint) (*model.Department, error)
+	GetAll(ctx context.Context) ([]model.Department, error)
+	Update(ctx context.Context, department *model.Department) error
+	Delete(ctx context.Context, id uint) error
+	AssignUser(ctx context.Context, departmentID, userID uint) error
+	RemoveUser(ctx context.Context, departmentID, userID uint) error
+	GetMembers(ctx context.Context, departmentID uint) ([]model.User, error)
+}
+
+type departmentService struct {
+	departmentRepo repository.DepartmentRepository
+	userRepo       repository.UserRepository
+}
+
+func NewDepartmentService(departmentRepo repository.DepartmentRepository, userRepo repository.UserRepository) DepartmentService {
+	return &departmentService{
+		departmentRepo: departmentRepo,
+		userRepo:       userRepo,
+	}
+}
+
+func (s *departmentService) Create(ctx context.Context, department *model.Department) error {
+	return s.departmentRepo.Create(ctx, department)
+}
+
+func (s *departmentService) GetByID(ctx context.Context, id uint) (*model.Department, error) {
+	department, err := s.departmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrDepartmentNotFound
+	}
+	return department, nil
+}
+
+func (s *departmentService) GetAll(ctx context.Context) ([]model.Department, error) {
+	return s.departmentRepo.GetAll(ctx)
+}
+
+func (s *departmentService) Update(ctx context.Context, department *model.Department) error {
+	existing, err := s.departmentRepo.GetByID(ctx, department.ID)
+	if err != nil {
+		return ErrDepartmentNotFound
+	}
+
+	existing.Name = department.Name
+	existing.Description = department.Description
+
+	if err := s.departmentRepo.Update(ctx, existing); err != nil {
+		return err
+	}
+	*department = *existing
+	return nil
+}
+
+func (s *departmentService) Delete(ctx context.Context, id uint) error {
+	if _, err := s.departmentRepo.GetByID(ctx, id); err != nil {
+		return ErrDepartmentNotFound
+	}
+
+	// Refuse to orphan members, including deleted ones that may be restored; they must be moved or removed first
+	members, err := s.userRepo.CountByDepartmentWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	if members > 0 {
+		return ErrDepartmentNotEmpty
+	}
+
+	return s.departmentRepo.Delete(ctx, id)
+}
+
+func (s *departmentService) AssignUser(ctx context.Context, departmentID, userID uint) error {
+	if _, err := s.departmentRepo.GetByID(ctx, departmentID); err != nil {
+		return ErrDepartmentNotFound
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return ErrUserNotFound
+	}
+
+	return s.userRepo.UpdateDepartment(ctx, userID, &departmentID)
+}
+
+func (s *departmentService) RemoveUser(ctx context.Context, departmentID, userID uint) error {
+	// Deleted users can be removed too, otherwise they would keep the department from being deleted
+	user, err := s.userRepo.GetByIDWithDeleted(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.DepartmentID == nil || *user.DepartmentID != departmentID {
+		return ErrUserNotInDept
+	}
+
+	return s.userRepo.UpdateDepartment(ctx, userID, nil)
+}
+
+func (s *departmentService) GetMembers(ctx context.Context, departmentID uint) ([]model.User, error) {
+	if _, err := s.departmentRepo.GetByID(ctx, departmentID); err != nil {
+		return nil, ErrDepartmentNotFound
+	}
+	return s.userRepo.GetByDepartment(ctx, departmentID)
+}
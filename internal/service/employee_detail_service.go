@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+)
+
+var (
+	ErrEmployeeDetailNotFound = errors.New("employee profile not found")
+	ErrEmployeeIDTaken        = errors.New("employee ID is already assigned to another user")
+)
+
+type EmployeeDetailService interface {
+	GetByUserID(ctx context.Context, userID uint) (*model.EmployeeDetail, error)
+	// Save creates or replaces the user's profile and, when departmentID is set, moves the user to that department
+	Save(ctx context.Context, detail *model.EmployeeDetail, departmentID *uint) error
+	Delete(ctx context.Context, userID uint) error
+}
+
+type employeeDetailService struct {
+	detailRepo     repository.EmployeeDetailRepository
+	userRepo       repository.UserRepository
+	departmentRepo repository.DepartmentRepository
+}
+
+func NewEmployeeDetailService(
+	detailRepo repository.EmployeeDetailRepository,
+	userRepo repository.UserRepository,
+	departmentRepo repository.DepartmentRepository,
+) EmployeeDetailService {
+	return &employeeDetailService{
+		detailRepo:     detailRepo,
+		userRepo:       userRepo,
+		departmentRepo: departmentRepo,
+	}
+}
+
+func (s *employeeDetailService) GetByUserID(ctx context.Context, userID uint) (*model.EmployeeDetail, error) {
+	detail, err := s.detailRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, ErrEmployeeDetailNotFound
+	}
+	return detail, nil
+}
+
+func (s *employeeDetailService) Save(ctx context.Context, detail *model.EmployeeDetail, departmentID *uint) error {
+	if _, err := s.userRepo.GetByID(ctx, detail.UserID); err != nil {
+		return ErrUserNotFound
+	}
+
+	if departmentID != nil {
+		if _, err := s.departmentRepo.GetByID(ctx, *departmentID); err != nil {
+			return ErrDepartmentNotFound
+		}
+	}
+
+	// Employee numbers are unique across the company
+	if owner, err := s.detailRepo.GetByEmployeeID(ctx, detail.EmployeeID); err == nil && owner.UserID != detail.UserID {
+		return ErrEmployeeIDTaken
+	}
+
+	existing, err := s.detailRepo.GetByUserID(ctx, detail.UserID)
+	if err != nil {
+		err = s.detailRepo.Create(ctx, detail)
+	} else {
+		existing.EmployeeID = detail.EmployeeID
+		existing.Position = detail.Position
+		existing.JoinDate = detail.JoinDate
+		err = s.detailRepo.Update(ctx, existing)
+		*detail = *existing
+	}
+	if err != nil {
+		return err
+	}
+
+	if departmentID != nil {
+		return s.userRepo.UpdateDepartment(ctx, detail.UserID, departmentID)
+	}
+	return nil
+}
+
+func (s *employeeDetailService) Delete(ctx context.Context, userID uint) error {
+	if _, err := s.detailRepo.GetByUserID(ctx, userID); err != nil {
+		return ErrEmployeeDetailNotFound
+	}
+	return s.detailRepo.DeleteByUserID(ctx, userID)
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+)
+
+var (
+	ErrGeofenceNotFound      = errors.New("geofence not found")
+	ErrOutsideGeofence       = errors.New("location is outside every allowed office area")
+	ErrLocationRequired      = errors.New("latitude and longitude are required")
+	ErrInvalidGeofencePolicy = errors.New("geofence policy must be 'off', 'flag' or 'reject'")
+)
+
+// GeofencePolicy decides what happens to check-ins outside every office geofence
+type GeofencePolicy string
+
+const (
+	// GeofencePolicyOff skips geofence validation entirely
+	GeofencePolicyOff GeofencePolicy = "off"
+	// GeofencePolicyFlag accepts check-ins outside the geofences and flags them as remote
+	GeofencePolicyFlag GeofencePolicy = "flag"
+	// GeofencePolicyReject refuses check-ins outside the geofences or without coordinates
+	GeofencePolicyReject GeofencePolicy = "reject"
+)
+
+// ParseGeofencePolicy converts a configuration value into a GeofencePolicy, defaulting to flag when empty
+func ParseGeofencePolicy(value string) (GeofencePolicy, error) {
+	switch GeofencePolicy(value) {
+	case "":
+		return GeofencePolicyFlag, nil
+	case GeofencePolicyOff, GeofencePolicyFlag, GeofencePolicyReject:
+		return GeofencePolicy(value), nil
+	default:
+		return "", ErrInvalidGeofencePolicy
+	}
+}
+
+// GeoPoint is a WGS84 coordinate reported by the client
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// coordinates splits the point into nullable columns, returning nils for a nil point
+func (p *GeoPoint) coordinates() (*float64, *float64) {
+	if p == nil {
+		return nil, nil
+	}
+	latitude, longitude := p.Latitude, p.Longitude
+	return &latitude, &longitude
+}
+
+// GeofenceResult is the outcome of validating a location against the office geofences
+type GeofenceResult struct {
+	// GeofenceID is the office area the location falls in, if any
+	GeofenceID *uint
+	// Remote is set when the location could not be matched to any office area
+	Remote bool
+}
+
+type GeofenceService interface {
+	Create(ctx context.Context, geofence *model.Geofence) error
+	GetByID(ctx context.Context, id uint) (*model.Geofence, error)
+	GetAll(ctx context.Context) ([]model.Geofence, error)
+	Update(ctx context.Context, geofence *model.Geofence) error
+	Delete(ctx context.Context, id uint) error
+	// Verify applies the configured geofence policy to a reported location, which may be nil
+	Verify(ctx context.Context, point *GeoPoint) (*GeofenceResult, error)
+}
+
+type geofenceService struct {
+	geofenceRepo repository.GeofenceRepository
+	config       *AttendanceConfig
+}
+
+func NewGeofenceService(geofenceRepo repository.GeofenceRepository, config *AttendanceConfig) GeofenceService {
+	return &geofenceService{
+		geofenceRepo: geofenceRepo,
+		config:       config,
+	}
+}
+
+func (s *geofenceService) Create(ctx context.Context, geofence *model.Geofence) error {
+	return s.geofenceRepo.Create(ctx, geofence)
+}
+
+func (s *geofenceService) GetByID(ctx context.Context, id uint) (*model.Geofence, error) {
+	geofence, err := s.geofenceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrGeofenceNotFound
+	}
+	return geofence, nil
+}
+
+func (s *geofenceService) GetAll(ctx context.Context) ([]model.Geofence, error) {
+	return s.geofenceRepo.GetAll(ctx)
+}
+
+func (s *geofenceService) Update(ctx context.Context, geofence *model.Geofence) error {
+	existing, err := s.geofenceRepo.GetByID(ctx, geofence.ID)
+	if err != nil {
+		return ErrGeofenceNotFound
+	}
+
+	existing.Name = geofence.Name
+	existing.Latitude = geofence.Latitude
+	existing.Longitude = geofence.Longitude
+	existing.RadiusMeters = geofence.RadiusMeters
+	if err := s.geofenceRepo.Update(ctx, existing); err != nil {
+		return err
+	}
+	*geofence = *existing
+	return nil
+}
+
+func (s *geofenceService) Delete(ctx context.Context, id uint) error {
+	if _, err := s.geofenceRepo.GetByID(ctx, id); err != nil {
+		return ErrGeofenceNotFound
+	}
+	return s.geofenceRepo.Delete(ctx, id)
+}
+
+func (s *geofenceService) Verify(ctx context.Context, point *GeoPoint) (*GeofenceResult, error) {
+	if s.config.GeofencePolicy == GeofencePolicyOff {
+		return &GeofenceResult{}, nil
+	}
+
+	geofences, err := s.geofenceRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Nothing to validate against until an admin defines at least one office
+	if len(geofences) == 0 {
+		return &GeofenceResult{}, nil
+	}
+
+	if point == nil {
+		if s.config.GeofencePolicy == GeofencePolicyReject {
+			return nil, ErrLocationRequired
+		}
+		return &GeofenceResult{Remote: true}, nil
+	}
+
+	// Pick the closest office whose radius contains the point
+	var match *model.Geofence
+	closest := math.Inf(1)
+	for i := range geofences {
+		distance := distanceMeters(*point, GeoPoint{Latitude: geofences[i].Latitude, Longitude: geofences[i].Longitude})
+		if distance <= geofences[i].RadiusMeters && distance < closest {
+			match = &geofences[i]
+			closest = distance
+		}
+	}
+
+	if match == nil {
+		if s.config.GeofencePolicy == GeofencePolicyReject {
+			return nil, ErrOutsideGeofence
+		}
+		return &GeofenceResult{Remote: true}, nil
+	}
+	return &GeofenceResult{GeofenceID: &match.ID}, nil
+}
+
+// distanceMeters returns the great-circle distance between two points using the haversine formula
+func distanceMeters(a, b GeoPoint) float64 {
+	const earthRadiusMeters = 6371000
+
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	deltaLat := (b.Latitude - a.Latitude) * math.Pi / 180
+	deltaLng := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLng/2)*math.Sin(deltaLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
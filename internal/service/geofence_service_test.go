@@ -0,0 +1,35 @@
+package service
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceMeters(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     GeoPoint
+		expected float64
+	}{
+		{name: "same point", a: GeoPoint{52.52, 13.405}, b: GeoPoint{52.52, 13.405}, expected: 0},
+		{name: "one degree of latitude", a: GeoPoint{0, 0}, b: GeoPoint{1, 0}, expected: 111194.927},
+		{name: "one degree of longitude at the equator", a: GeoPoint{0, 0}, b: GeoPoint{0, 1}, expected: 111194.927},
+		{name: "one degree of longitude at 60 degrees north", a: GeoPoint{60, 0}, b: GeoPoint{60, 1}, expected: 55596.934},
+		{name: "geofence sized step", a: GeoPoint{52.52, 13.405}, b: GeoPoint{52.521, 13.405}, expected: 111.195},
+		{name: "antipodes on the equator", a: GeoPoint{0, 0}, b: GeoPoint{0, 180}, expected: 20015086.796},
+		{name: "across the antimeridian", a: GeoPoint{0, 179.5}, b: GeoPoint{0, -179.5}, expected: 111194.927},
+		{name: "paris to london", a: GeoPoint{48.8566, 2.3522}, b: GeoPoint{51.5074, -0.1278}, expected: 343556.060},
+		{name: "sydney to new york", a: GeoPoint{-33.8688, 151.2093}, b: GeoPoint{40.7128, -74.006}, expected: 15988755.507},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := distanceMeters(tt.a, tt.b); math.Abs(got-tt.expected) > 0.01 {
+				t.Errorf("expected %.3f m, got %.3f m", tt.expected, got)
+			}
+			if got := distanceMeters(tt.b, tt.a); math.Abs(got-tt.expected) > 0.01 {
+				t.Errorf("expected %.3f m in the other direction, got %.3f m", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrHolidayNotFound      = errors.New("holiday not found")
+	ErrHolidayExists        = errors.New("a holiday already exists on this date")
+	ErrHolidayOutOfYear     = errors.New("holiday date is outside the imported year")
+	ErrDuplicateHoliday     = errors.New("import contains the same date more than once")
+	ErrCheckInOnHoliday     = errors.New("check-in is not allowed on a holiday")
+	ErrInvalidHolidayPolicy = errors.New("holiday policy must be 'reject' or 'overtime'")
+)
+
+// HolidayPolicy decides what happens when someone checks in on a holiday
+type HolidayPolicy string
+
+const (
+	// HolidayPolicyReject refuses check-ins on holidays
+	HolidayPolicyReject HolidayPolicy = "reject"
+	// HolidayPolicyOvertime accepts check-ins on holidays and flags them as holiday overtime
+	HolidayPolicyOvertime HolidayPolicy = "overtime"
+)
+
+// ParseHolidayPolicy converts a configuration value into a HolidayPolicy, defaulting to overtime when empty
+func ParseHolidayPolicy(value string) (HolidayPolicy, error) {
+	switch HolidayPolicy(value) {
+	case "":
+		return HolidayPolicyOvertime, nil
+	case HolidayPolicyReject, HolidayPolicyOvertime:
+		return HolidayPolicy(value), nil
+	default:
+		return "", ErrInvalidHolidayPolicy
+	}
+}
+
+type HolidayService interface {
+	Create(ctx context.Context, holiday *model.Holiday) error
+	GetByID(ctx context.Context, id uint) (*model.Holiday, error)
+	GetByYear(ctx context.Context, year int) ([]model.Holiday, error)
+	GetBetween(ctx context.Context, startDate, endDate time.Time) ([]model.Holiday, error)
+	Update(ctx context.Context, holiday *model.Holiday) error
+	Delete(ctx context.Context, id uint) error
+	ImportYear(ctx context.Context, year int, holidays []model.Holiday, replace bool) error
+	// GetHoliday returns the holiday falling on the given day, or nil when it is a regular day
+	GetHoliday(ctx context.Context, day time.Time) (*model.Holiday, error)
+}
+
+type holidayService struct {
+	holidayRepo repository.HolidayRepository
+}
+
+func NewHolidayService(holidayRepo repository.HolidayRepository) HolidayService {
+	return &holidayService{holidayRepo: holidayRepo}
+}
+
+func (s *holidayService) Create(ctx context.Context, holiday *model.Holiday) error {
+	if existing, _ := s.holidayRepo.GetByDate(ctx, holiday.Date); existing != nil {
+		return ErrHolidayExists
+	}
+	return s.holidayRepo.Create(ctx, holiday)
+}
+
+func (s *holidayService) GetByID(ctx context.Context, id uint) (*model.Holiday, error) {
+	holiday, err := s.holidayRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrHolidayNotFound
+	}
+	return holiday, nil
+}
+
+func (s *holidayService) GetByYear(ctx context.Context, year int) ([]model.Holiday, error) {
+	startDate, endDate := yearBounds(year)
+	return s.holidayRepo.GetBetween(ctx, startDate, endDate)
+}
+
+func (s *holidayService) GetBetween(ctx context.Context, startDate, endDate time.Time) ([]model.Holiday, error) {
+	return s.holidayRepo.GetBetween(ctx, startDate, endDate)
+}
+
+func (s *holidayService) Update(ctx context.Context, holiday *model.Holiday) error {
+	existing, err := s.holidayRepo.GetByID(ctx, holiday.ID)
+	if err != nil {
+		return ErrHolidayNotFound
+	}
+
+	if other, _ := s.holidayRepo.GetByDate(ctx, holiday.Date); other != nil && other.ID != holiday.ID {
+		return ErrHolidayExists
+	}
+
+	existing.Name = holiday.Name
+	existing.Date = holiday.Date
+	existing.Description = holiday.Description
+	if err := s.holidayRepo.Update(ctx, existing); err != nil {
+		return err
+	}
+	*holiday = *existing
+	return nil
+}
+
+func (s *holidayService) Delete(ctx context.Context, id uint) error {
+	if _, err := s.holidayRepo.GetByID(ctx, id); err != nil {
+		return ErrHolidayNotFound
+	}
+	return s.holidayRepo.Delete(ctx, id)
+}
+
+func (s *holidayService) ImportYear(ctx context.Context, year int, holidays []model.Holiday, replace bool) error {
+	startDate, endDate := yearBounds(year)
+
+	seen := make(map[string]bool, len(holidays))
+	for _, holiday := range holidays {
+		if holiday.Date.Year() != year {
+			return ErrHolidayOutOfYear
+		}
+		key := holiday.Date.Format("2006-01-02")
+		if seen[key] {
+			return ErrDuplicateHoliday
+		}
+		seen[key] = true
+	}
+
+	if !replace {
+		existing, err := s.holidayRepo.GetBetween(ctx, startDate, endDate)
+		if err != nil {
+			return err
+		}
+		for _, holiday := range existing {
+			if seen[holiday.Date.Format("2006-01-02")] {
+				return ErrHolidayExists
+			}
+		}
+	}
+
+	return s.holidayRepo.Import(ctx, holidays, startDate, endDate, replace)
+}
+
+func (s *holidayService) GetHoliday(ctx context.Context, day time.Time) (*model.Holiday, error) {
+	holiday, err := s.holidayRepo.GetByDate(ctx, day)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return holiday, err
+}
+
+// yearBounds returns the first and last calendar day of year in local time
+func yearBounds(year int) (time.Time, time.Time) {
+	startDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	return startDate, startDate.AddDate(1, 0, -1)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+	"absence/pkg/jwt"
+	"absence/pkg/notifier"
+)
+
+var (
+	ErrInvitationNotFound   = errors.New("invitation not found")
+	ErrInvitationNotPending = errors.New("invitation has already been accepted or revoked")
+	ErrInvalidInvitation    = errors.New("invalid or expired invitation")
+)
+
+type InvitationService interface {
+	// Create stores the invitation and emails its link, replacing earlier open invitations for the same email
+	Create(ctx context.Context, invitation *model.Invitation) error
+	GetAll(ctx context.Context) ([]model.Invitation, error)
+	Revoke(ctx context.Context, id uint) error
+	// Accept redeems an invitation, creating the account with the invited email, role and department
+	Accept(ctx context.Context, token, username, fullName, password string) (*model.User, error)
+}
+
+type invitationService struct {
+	invitationRepo repository.InvitationRepository
+	userRepo       repository.UserRepository
+	roleRepo       repository.RoleRepository
+	departmentRepo repository.DepartmentRepository
+	notifier       notifier.Notifier
+	config         *RegistrationConfig
+}
+
+func NewInvitationService(
+	invitationRepo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	departmentRepo repository.DepartmentRepository,
+	notifier notifier.Notifier,
+	config *RegistrationConfig,
+) InvitationService {
+	return &invitationService{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		departmentRepo: departmentRepo,
+		notifier:       notifier,
+		config:         config,
+	}
+}
+
+func (s *invitationService) Create(ctx context.Context, invitation *model.Invitation) error {
+	if _, err := s.roleRepo.GetByName(ctx, invitation.Role); err != nil {
+		return ErrRoleNotFound
+	}
+	if invitation.DepartmentID != nil {
+		if _, err := s.departmentRepo.GetByID(ctx, *invitation.DepartmentID); err != nil {
+			return ErrDepartmentNotFound
+		}
+	}
+	if err := checkAvailable(ctx, s.userRepo, "", invitation.Email); err != nil {
+		return err
+	}
+
+	// Only the latest invitation for an address stays valid
+	if err := s.invitationRepo.RevokePendingForEmail(ctx, invitation.Email); err != nil {
+		return err
+	}
+
+	token, err := jwt.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	invitation.TokenHash = jwt.HashToken(token)
+	invitation.ExpiresAt = time.Now().Add(s.config.InvitationTTL)
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return err
+	}
+	invitation.Status = model.InvitationStatusPending
+
+	link := token
+	if s.config.InvitationURL != "" {
+		link = s.config.InvitationURL + "?token=" + token
+	}
+	msg := notifier.Message{
+		To:      invitation.Email,
+		Subject: "You have been invited",
+		Body: fmt.Sprintf("Hi,\n\nYou have been invited to create an account. Use the following link to choose your username and password. It expires on %s and works once.\n\n%s",
+			invitation.ExpiresAt.Format(time.RFC1123), link),
+	}
+	if err := s.notifier.Send(ctx, msg); err != nil {
+		log.Printf("failed to send invitation %d: %v", invitation.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *invitationService) GetAll(ctx context.Context) ([]model.Invitation, error) {
+	invitations, err := s.invitationRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range invitations {
+		invitations[i].Status = invitations[i].StatusAt(now)
+	}
+	return invitations, nil
+}
+
+func (s *invitationService) Revoke(ctx context.Context, id uint) error {
+	if _, err := s.invitationRepo.GetByID(ctx, id); err != nil {
+		return ErrInvitationNotFound
+	}
+
+	revoked, err := s.invitationRepo.Revoke(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrInvitationNotPending
+	}
+	return nil
+}
+
+func (s *invitationService) Accept(ctx context.Context, token, username, fullName, password string) (*model.User, error) {
+	invitation, err := s.invitationRepo.GetByHash(ctx, jwt.HashToken(token))
+	if err != nil || invitation.StatusAt(time.Now()) != model.InvitationStatusPending {
+		return nil, ErrInvalidInvitation
+	}
+	if err := checkAvailable(ctx, s.userRepo, username, invitation.Email); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user := &model.User{
+		Username:     username,
+		Password:     hashedPassword,
+		FullName:     fullName,
+		Email:        invitation.Email,
+		Role:         invitation.Role,
+		DepartmentID: invitation.DepartmentID,
+		Active:       true,
+	}
+
+	accepted, err := s.invitationRepo.Accept(ctx, invitation.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, ErrInvalidInvitation
+	}
+	return user, nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLeaveNotFound       = errors.New("leave request not found")
+	ErrLeaveTypeNotFound   = errors.New("leave type not found")
+	ErrInvalidLeavePeriod  = errors.New("end date must not be before start date")
+	ErrLeaveOverlap        = errors.New("leave request overlaps an existing leave")
+	ErrLeaveNotPending     = errors.New("leave request is no longer pending")
+	ErrLeaveNotCancellable = errors.New("leave request can no longer be cancelled")
+	ErrLeaveSelfApproval   = errors.New("cannot decide on your own leave request")
+	ErrLeaveForbidden      = errors.New("leave request belongs to another user")
+	ErrLeaveOutOfScope     = errors.New("leave request belongs to a user outside your department")
+)
+
+type LeaveService interface {
+	CreateLeaveType(ctx context.Context, leaveType *model.LeaveType) error
+	GetLeaveTypes(ctx context.Context) ([]model.LeaveType, error)
+	Submit(ctx context.Context, leave *model.LeaveRequest) error
+	Approve(ctx context.Context, id, approverID uint, scope ApprovalScope) (*model.LeaveRequest, error)
+	Reject(ctx context.Context, id, approverID uint, scope ApprovalScope) (*model.LeaveRequest, error)
+	Cancel(ctx context.Context, id, userID uint) (*model.LeaveRequest, error)
+	GetByID(ctx context.Context, id uint) (*model.LeaveRequest, error)
+	// GetVisible returns a leave request if it falls within the viewer's scope
+	GetVisible(ctx context.Context, id, viewerID uint, scope ApprovalScope) (*model.LeaveRequest, error)
+	// GetVisibleByStatus lists the leave requests within the viewer's scope, optionally filtered by status
+	GetVisibleByStatus(ctx context.Context, viewerID uint, scope ApprovalScope, status string) ([]model.LeaveRequest, error)
+	GetByStatus(ctx context.Context, status string) ([]model.LeaveRequest, error)
+	GetUserLeaves(ctx context.Context, userID uint) ([]model.LeaveRequest, error)
+	// GetApprovedLeave returns the approved leave covering the given day, or nil when there is none
+	GetApprovedLeave(ctx context.Context, userID uint, day time.Time) (*model.LeaveRequest, error)
+}
+
+type leaveService struct {
+	leaveRepo repository.LeaveRepository
+	userRepo  repository.UserRepository
+}
+
+func NewLeaveService(leaveRepo repository.LeaveRepository, userRepo repository.UserRepository) LeaveService {
+	return &leaveService{
+		leaveRepo: leaveRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *leaveService) CreateLeaveType(ctx context.Context, leaveType *model.LeaveType) error {
+	return s.leaveRepo.CreateType(ctx, leaveType)
+}
+
+func (s *leaveService) GetLeaveTypes(ctx context.Context) ([]model.LeaveType, error) {
+	return s.leaveRepo.GetTypes(ctx)
+}
+
+func (s *leaveService) Submit(ctx context.Context, leave *model.LeaveRequest) error {
+	if leave.EndDate.Before(leave.StartDate) {
+		return ErrInvalidLeavePeriod
+	}
+
+	if _, err := s.leaveRepo.GetTypeByID(ctx, leave.LeaveTypeID); err != nil {
+		return ErrLeaveTypeNotFound
+	}
+
+	// Reject requests that overlap a leave which is still pending or already approved
+	overlapping, err := s.leaveRepo.GetOverlapping(ctx, leave.UserID, leave.StartDate, leave.EndDate)
+	if err != nil {
+		return err
+	}
+	if len(overlapping) > 0 {
+		return ErrLeaveOverlap
+	}
+
+	leave.Status = model.LeaveStatusPending
+	leave.ApprovedBy = nil
+	leave.DecidedAt = nil
+
+	return s.leaveRepo.Create(ctx, leave)
+}
+
+func (s *leaveService) Approve(ctx context.Context, id, approverID uint, scope ApprovalScope) (*model.LeaveRequest, error) {
+	return s.decide(ctx, id, approverID, scope, model.LeaveStatusApproved)
+}
+
+func (s *leaveService) Reject(ctx context.Context, id, approverID uint, scope ApprovalScope) (*model.LeaveRequest, error) {
+	return s.decide(ctx, id, approverID, scope, model.LeaveStatusRejected)
+}
+
+// decide moves a pending leave request to its final approved or rejected state
+func (s *leaveService) decide(ctx context.Context, id, approverID uint, scope ApprovalScope, status string) (*model.LeaveRequest, error) {
+	leave, err := s.leaveRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrLeaveNotFound
+	}
+
+	if err := s.checkScope(ctx, leave, approverID, scope); err != nil {
+		return nil, err
+	}
+	if leave.Status != model.LeaveStatusPending {
+		return nil, ErrLeaveNotPending
+	}
+	if leave.UserID == approverID {
+		return nil, ErrLeaveSelfApproval
+	}
+
+	now := time.Now()
+	leave.Status = status
+	leave.ApprovedBy = &approverID
+	leave.DecidedAt = &now
+
+	if err := s.leaveRepo.Update(ctx, leave); err != nil {
+		return nil, err
+	}
+	return leave, nil
+}
+
+// Cancel withdraws a pending leave, or an approved one that has not started yet
+func (s *leaveService) Cancel(ctx context.Context, id, userID uint) (*model.LeaveRequest, error) {
+	leave, err := s.leaveRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrLeaveNotFound
+	}
+
+	if leave.UserID != userID {
+		return nil, ErrLeaveForbidden
+	}
+
+	today := startOfDay(time.Now())
+	switch leave.Status {
+	case model.LeaveStatusPending:
+	case model.LeaveStatusApproved:
+		if !leave.StartDate.After(today) {
+			return nil, ErrLeaveNotCancellable
+		}
+	default:
+		return nil, ErrLeaveNotCancellable
+	}
+
+	leave.Status = model.LeaveStatusCancelled
+	if err := s.leaveRepo.Update(ctx, leave); err != nil {
+		return nil, err
+	}
+	return leave, nil
+}
+
+func (s *leaveService) GetByID(ctx context.Context, id uint) (*model.LeaveRequest, error) {
+	return s.leaveRepo.GetByID(ctx, id)
+}
+
+func (s *leaveService) GetVisible(ctx context.Context, id, viewerID uint, scope ApprovalScope) (*model.LeaveRequest, error) {
+	leave, err := s.leaveRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrLeaveNotFound
+	}
+
+	// Own requests are always visible, whatever the scope
+	if leave.UserID != viewerID {
+		if err := s.checkScope(ctx, leave, viewerID, scope); err != nil {
+			return nil, err
+		}
+	}
+	return leave, nil
+}
+
+func (s *leaveService) GetVisibleByStatus(ctx context.Context, viewerID uint, scope ApprovalScope, status string) ([]model.LeaveRequest, error) {
+	switch scope {
+	case ApprovalScopeAll:
+		return s.leaveRepo.GetByStatus(ctx, status)
+	case ApprovalScopeDepartment:
+		departmentID, err := approvalDepartment(ctx, s.userRepo, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if departmentID == nil {
+			return s.leaveRepo.GetUserLeavesByStatus(ctx, viewerID, status)
+		}
+		return s.leaveRepo.GetByDepartment(ctx, *departmentID, status)
+	default:
+		return s.leaveRepo.GetUserLeavesByStatus(ctx, viewerID, status)
+	}
+}
+
+func (s *leaveService) GetByStatus(ctx context.Context, status string) ([]model.LeaveRequest, error) {
+	return s.leaveRepo.GetByStatus(ctx, status)
+}
+
+func (s *leaveService) GetUserLeaves(ctx context.Context, userID uint) ([]model.LeaveRequest, error) {
+	return s.leaveRepo.GetUserLeaves(ctx, userID)
+}
+
+func (s *leaveService) GetApprovedLeave(ctx context.Context, userID uint, day time.Time) (*model.LeaveRequest, error) {
+	leave, err := s.leaveRepo.GetApprovedOn(ctx, userID, day)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return leave, err
+}
+
+// checkScope verifies that the actor may act on the leave request within the given scope
+func (s *leaveService) checkScope(ctx context.Context, leave *model.LeaveRequest, actorID uint, scope ApprovalScope) error {
+	return checkApprovalScope(ctx, s.userRepo, scope, actorID, leave.UserID, ErrLeaveForbidden, ErrLeaveOutOfScope)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+)
+
+var (
+	ErrLoginThrottled      = errors.New("too many failed login attempts, try again later")
+	ErrUnlockTargetMissing = errors.New("username or ip is required")
+)
+
+// LoginThrottledError is returned while a username or IP address has to wait before trying again
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrLoginThrottled.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrLoginThrottled
+}
+
+// LoginThrottleConfig holds the brute-force protection limits
+type LoginThrottleConfig struct {
+	// MaxFailures is the number of failed attempts after which a username is locked out
+	MaxFailures int
+	// IPMaxFailures is the number of failed attempts, across all usernames, after which an IP address is locked out
+	IPMaxFailures int
+	// LockoutDuration is how long a lockout lasts; failures older than this no longer count
+	LockoutDuration time.Duration
+	// BaseDelay is the wait after the first failure of a username; it doubles with every further failure
+	BaseDelay time.Duration
+}
+
+type LoginThrottleService interface {
+	// Reserve stores the attempt as a failure before the credentials are verified, or returns a
+	// *LoginThrottledError without storing it when the username or IP address must wait. Concurrent
+	// attempts thereby count against each other instead of all passing before the first failure is stored.
+	Reserve(ctx context.Context, attempt *model.LoginAttempt) error
+	// Succeed marks a reserved attempt successful and clears the earlier failures of its username
+	Succeed(ctx context.Context, attempt *model.LoginAttempt) error
+	// Release drops a reserved attempt whose credentials were right but not enough to log in, e.g. while
+	// a second factor is outstanding
+	Release(ctx context.Context, attempt *model.LoginAttempt) error
+	// Record stores an attempt; a successful login clears the earlier failures of the username
+	Record(ctx context.Context, attempt *model.LoginAttempt) error
+	// Unlock clears the failures of a username and/or IP address so they can log in again right away
+	Unlock(ctx context.Context, username, ip string) error
+	GetAttempts(ctx context.Context, filter repository.LoginAttemptFilter) ([]model.LoginAttempt, error)
+}
+
+type loginThrottleService struct {
+	attemptRepo repository.LoginAttemptRepository
+	config      *LoginThrottleConfig
+}
+
+func NewLoginThrottleService(attemptRepo repository.LoginAttemptRepository, config *LoginThrottleConfig) LoginThrottleService {
+	return &loginThrottleService{
+		attemptRepo: attemptRepo,
+		config:      config,
+	}
+}
+
+func (s *loginThrottleService) Reserve(ctx context.Context, attempt *model.LoginAttempt) error {
+	now := time.Now()
+	attempt.Outcome = model.LoginOutcomeFailure
+
+	// Unknown usernames are throttled exactly like real ones so lockouts do not reveal which accounts exist
+	return s.attemptRepo.Reserve(ctx, attempt, now.Add(-s.config.LockoutDuration), func(failures, ipFailures []model.LoginAttempt) error {
+		return s.check(failures, ipFailures, now)
+	})
+}
+
+func (s *loginThrottleService) Succeed(ctx context.Context, attempt *model.LoginAttempt) error {
+	if err := s.attemptRepo.UpdateOutcome(ctx, attempt.ID, model.LoginOutcomeSuccess); err != nil {
+		return err
+	}
+	attempt.Outcome = model.LoginOutcomeSuccess
+	return s.attemptRepo.ClearUsername(ctx, attempt.Username)
+}
+
+func (s *loginThrottleService) Release(ctx context.Context, attempt *model.LoginAttempt) error {
+	return s.attemptRepo.Delete(ctx, attempt.ID)
+}
+
+// check returns a *LoginThrottledError when the recent failures of a username and IP address, newest
+// first, require waiting before the next attempt
+func (s *loginThrottleService) check(failures, ipFailures []model.LoginAttempt, now time.Time) error {
+	var retryAt time.Time
+	if count := len(failures); count > 0 {
+		if count >= s.config.MaxFailures {
+			retryAt = failures[0].CreatedAt.Add(s.config.LockoutDuration)
+		} else {
+			retryAt = failures[0].CreatedAt.Add(s.delay(count))
+		}
+	}
+
+	if len(ipFailures) >= s.config.IPMaxFailures {
+		if ipRetryAt := ipFailures[0].CreatedAt.Add(s.config.LockoutDuration); ipRetryAt.After(retryAt) {
+			retryAt = ipRetryAt
+		}
+	}
+
+	if retryAt.After(now) {
+		return &LoginThrottledError{RetryAfter: retryAt.Sub(now)}
+	}
+	return nil
+}
+
+// delay doubles the base delay with every failure, never exceeding a full lockout
+func (s *loginThrottleService) delay(failures int) time.Duration {
+	delay := s.config.BaseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= s.config.LockoutDuration {
+			return s.config.LockoutDuration
+		}
+	}
+	return delay
+}
+
+func (s *loginThrottleService) Record(ctx context.Context, attempt *model.LoginAttempt) error {
+	if err := s.attemptRepo.Create(ctx, attempt); err != nil {
+		return err
+	}
+	// Failures from the same IP keep counting, otherwise one valid account would reset an attacker's budget
+	if attempt.Outcome == model.LoginOutcomeSuccess {
+		return s.attemptRepo.ClearUsername(ctx, attempt.Username)
+	}
+	return nil
+}
+
+func (s *loginThrottleService) Unlock(ctx context.Context, username, ip string) error {
+	if username == "" && ip == "" {
+		return ErrUnlockTargetMissing
+	}
+	if username != "" {
+		if err := s.attemptRepo.ClearUsername(ctx, username); err != nil {
+			return err
+		}
+	}
+	if ip != "" {
+		return s.attemptRepo.ClearIP(ctx, ip)
+	}
+	return nil
+}
+
+func (s *loginThrottleService) GetAttempts(ctx context.Context, filter repository.LoginAttemptFilter) ([]model.LoginAttempt, error) {
+	return s.attemptRepo.Find(ctx, filter)
+}
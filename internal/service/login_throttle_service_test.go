@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+)
+
+// loginAttemptRepositoryStub keeps attempts in memory; Reserve holds one lock for the whole check and
+// insert, like the named locks of the real repository do per username and IP address
+type loginAttemptRepositoryStub struct {
+	repository.LoginAttemptRepository
+
+	mu       sync.Mutex
+	attempts []model.LoginAttempt
+}
+
+func (r *loginAttemptRepositoryStub) Reserve(ctx context.Context, attempt *model.LoginAttempt, since time.Time, allow func(failures, ipFailures []model.LoginAttempt) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var failures, ipFailures []model.LoginAttempt
+	for i := len(r.attempts) - 1; i >= 0; i-- {
+		stored := r.attempts[i]
+		if stored.Outcome != model.LoginOutcomeFailure || stored.CreatedAt.Before(since) {
+			continue
+		}
+		if stored.Username == attempt.Username {
+			failures = append(failures, stored)
+		}
+		if stored.IP == attempt.IP {
+			ipFailures = append(ipFailures, stored)
+		}
+	}
+	if err := allow(failures, ipFailures); err != nil {
+		return err
+	}
+
+	attempt.ID = uint(len(r.attempts) + 1)
+	attempt.CreatedAt = time.Now()
+	r.attempts = append(r.attempts, *attempt)
+	return nil
+}
+
+func TestReserveThrottlesConcurrentAttempts(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  LoginThrottleConfig
+		allowed int
+	}{
+		{
+			name:    "delay after the first pending attempt",
+			config:  LoginThrottleConfig{MaxFailures: 5, IPMaxFailures: 100, LockoutDuration: time.Hour, BaseDelay: time.Minute},
+			allowed: 1,
+		},
+		{
+			name:    "username lockout",
+			config:  LoginThrottleConfig{MaxFailures: 3, IPMaxFailures: 100, LockoutDuration: time.Hour},
+			allowed: 3,
+		},
+		{
+			name:    "ip lockout",
+			config:  LoginThrottleConfig{MaxFailures: 100, IPMaxFailures: 4, LockoutDuration: time.Hour},
+			allowed: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewLoginThrottleService(&loginAttemptRepositoryStub{}, &tt.config)
+
+			const concurrent = 20
+			results := make(chan error, concurrent)
+			var wg sync.WaitGroup
+			for i := 0; i < concurrent; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					results <- svc.Reserve(context.Background(), &model.LoginAttempt{Username: "alice", IP: "192.0.2.1"})
+				}()
+			}
+			wg.Wait()
+			close(results)
+
+			allowed := 0
+			for err := range results {
+				switch {
+				case err == nil:
+					allowed++
+				case !errors.Is(err, ErrLoginThrottled):
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if allowed != tt.allowed {
+				t.Errorf("expected %d of %d concurrent attempts to pass, got %d", tt.allowed, concurrent, allowed)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	config := &LoginThrottleConfig{MaxFailures: 3, IPMaxFailures: 5, LockoutDuration: 15 * time.Minute, BaseDelay: time.Second}
+	svc := &loginThrottleService{config: config}
+
+	// failuresAt returns one failure per age, newest first
+	failuresAt := func(ages ...time.Duration) []model.LoginAttempt {
+		failures := make([]model.LoginAttempt, len(ages))
+		for i, age := range ages {
+			failures[i] = model.LoginAttempt{Outcome: model.LoginOutcomeFailure, CreatedAt: now.Add(-age)}
+		}
+		return failures
+	}
+
+	tests := []struct {
+		name       string
+		failures   []model.LoginAttempt
+		ipFailures []model.LoginAttempt
+		retryAfter time.Duration
+	}{
+		{
+			name: "no failures",
+		},
+		{
+			name:       "delay after one failure",
+			failures:   failuresAt(0),
+			ipFailures: failuresAt(0),
+			retryAfter: time.Second,
+		},
+		{
+			name:       "delay doubles with every failure",
+			failures:   failuresAt(time.Second, 2*time.Second),
+			ipFailures: failuresAt(time.Second, 2*time.Second),
+			retryAfter: time.Second,
+		},
+		{
+			name:       "delay has passed",
+			failures:   failuresAt(2*time.Second, 3*time.Second),
+			ipFailures: failuresAt(2*time.Second, 3*time.Second),
+		},
+		{
+			name:       "username locked out",
+			failures:   failuresAt(time.Minute, 2*time.Minute, 3*time.Minute),
+			ipFailures: failuresAt(time.Minute, 2*time.Minute, 3*time.Minute),
+			retryAfter: 14 * time.Minute,
+		},
+		{
+			name:       "username lockout has expired",
+			failures:   failuresAt(15*time.Minute, 16*time.Minute, 17*time.Minute),
+			ipFailures: failuresAt(15*time.Minute, 16*time.Minute, 17*time.Minute),
+		},
+		{
+			name:       "ip locked out across usernames",
+			ipFailures: failuresAt(time.Minute, 2*time.Minute, 3*time.Minute, 4*time.Minute, 5*time.Minute),
+			retryAfter: 14 * time.Minute,
+		},
+		{
+			name:       "ip below its limit",
+			ipFailures: failuresAt(time.Minute, 2*time.Minute, 3*time.Minute, 4*time.Minute),
+		},
+		{
+			name:       "ip lockout has expired",
+			ipFailures: failuresAt(15*time.Minute, 16*time.Minute, 17*time.Minute, 18*time.Minute, 19*time.Minute),
+		},
+		{
+			name:       "longer of username delay and ip lockout",
+			failures:   failuresAt(0),
+			ipFailures: failuresAt(0, time.Minute, 2*time.Minute, 3*time.Minute, 4*time.Minute),
+			retryAfter: 15 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.check(tt.failures, tt.ipFailures, now)
+			if tt.retryAfter == 0 {
+				if err != nil {
+					t.Fatalf("expected no throttling, got %v", err)
+				}
+				return
+			}
+
+			var throttled *LoginThrottledError
+			if !errors.As(err, &throttled) {
+				t.Fatalf("expected *LoginThrottledError, got %v", err)
+			}
+			if throttled.RetryAfter != tt.retryAfter {
+				t.Errorf("expected retry after %v, got %v", tt.retryAfter, throttled.RetryAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrRoleExists        = errors.New("role already exists")
+	ErrRoleBuiltIn       = errors.New("built-in role cannot be changed this way")
+	ErrRoleInUse         = errors.New("role is still assigned to users")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrUserPrivileged    = errors.New("user holds privileges you do not have")
+)
+
+type RoleService interface {
+	Create(ctx context.Context, role *model.Role) error
+	GetByID(ctx context.Context, id uint) (*model.Role, error)
+	GetAll(ctx context.Context) ([]model.Role, error)
+	Update(ctx context.Context, role *model.Role) error
+	Delete(ctx context.Context, id uint) error
+	// GetPermissions returns the permissions granted by the role with the given name
+	GetPermissions(ctx context.Context, name string) ([]string, error)
+	// AssignRole gives a user the role with the given name
+	AssignRole(ctx context.Context, userID uint, name string) (*model.User, error)
+	// CheckPrivileges returns ErrUserPrivileged when the user's role, deleted or not, grants a
+	// privileged permission the actor with the given permissions does not hold
+	CheckPrivileges(ctx context.Context, actorPermissions []string, userID uint) error
+}
+
+// Permissions are baked into access tokens, so the service revokes the tokens of every user whose
+// permissions it changes; they take effect on the next login or refresh.
+type roleService struct {
+	roleRepo     repository.RoleRepository
+	userRepo     repository.UserRepository
+	tokenService TokenService
+}
+
+func NewRoleService(
+	roleRepo repository.RoleRepository,
+	userRepo repository.UserRepository,
+	tokenService TokenService,
+) RoleService {
+	return &roleService{
+		roleRepo:     roleRepo,
+		userRepo:     userRepo,
+		tokenService: tokenService,
+	}
+}
+
+func (s *roleService) Create(ctx context.Context, role *model.Role) error {
+	if err := validatePermissions(role.Permissions); err != nil {
+		return err
+	}
+	if _, err := s.roleRepo.GetByName(ctx, role.Name); err == nil {
+		return ErrRoleExists
+	}
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+	return s.roleRepo.Create(ctx, role)
+}
+
+func (s *roleService) GetByID(ctx context.Context, id uint) (*model.Role, error) {
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	return role, nil
+}
+
+func (s *roleService) GetAll(ctx context.Context) ([]model.Role, error) {
+	return s.roleRepo.GetAll(ctx)
+}
+
+// Update changes the description and permissions of a role; role names are immutable because users reference them
+func (s *roleService) Update(ctx context.Context, role *model.Role) error {
+	existing, err := s.roleRepo.GetByID(ctx, role.ID)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+	// Taking permissions away from admin could lock everyone out
+	if existing.Name == model.RoleAdmin {
+		return ErrRoleBuiltIn
+	}
+	if err := validatePermissions(role.Permissions); err != nil {
+		return err
+	}
+
+	existing.Description = role.Description
+	existing.Permissions = role.Permissions
+	if existing.Permissions == nil {
+		existing.Permissions = []string{}
+	}
+	if err := s.roleRepo.Update(ctx, existing); err != nil {
+		return err
+	}
+	*role = *existing
+	return s.revokeHolders(ctx, existing.Name)
+}
+
+func (s *roleService) Delete(ctx context.Context, id uint) error {
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+	if role.Name == model.RoleAdmin || role.Name == model.RoleEmployee {
+		return ErrRoleBuiltIn
+	}
+
+	count, err := s.roleRepo.CountUsers(ctx, role.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+	return s.roleRepo.Delete(ctx, id)
+}
+
+func (s *roleService) GetPermissions(ctx context.Context, name string) ([]string, error) {
+	role, err := s.roleRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	return role.Permissions, nil
+}
+
+func (s *roleService) AssignRole(ctx context.Context, userID uint, name string) (*model.User, error) {
+	if _, err := s.roleRepo.GetByName(ctx, name); err != nil {
+		return nil, ErrRoleNotFound
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	user.Role = name
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.tokenService.RevokeUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *roleService) CheckPrivileges(ctx context.Context, actorPermissions []string, userID uint) error {
+	user, err := s.userRepo.GetByIDWithDeleted(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if slices.Contains(actorPermissions, model.PermissionAll) {
+		return nil
+	}
+
+	// A missing role grants nothing
+	role, err := s.roleRepo.GetByName(ctx, user.Role)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, permission := range model.PrivilegedPermissions {
+		if slices.Contains(role.Permissions, permission) && !slices.Contains(actorPermissions, permission) {
+			return ErrUserPrivileged
+		}
+	}
+	return nil
+}
+
+// revokeHolders revokes the tokens of every user assigned the role with the given name
+func (s *roleService) revokeHolders(ctx context.Context, name string) error {
+	userIDs, err := s.roleRepo.GetUserIDs(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := s.tokenService.RevokeUser(ctx, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePermissions(permissions []string) error {
+	for _, permission := range permissions {
+		if !slices.Contains(model.Permissions, permission) {
+			return fmt.Errorf("%w: %s", ErrUnknownPermission, permission)
+		}
+	}
+	return nil
+}
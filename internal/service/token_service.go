@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+	"absence/pkg/jwt"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
+
+// TokenPair is what a client receives on login and on every refresh
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	// ExpiresAt is when the access token expires
+	ExpiresAt   time.Time
+	Permissions []string
+}
+
+type TokenService interface {
+	// Issue creates a new access and refresh token pair for the user
+	Issue(ctx context.Context, user *model.User) (*TokenPair, error)
+	// Refresh rotates a refresh token, returning a new pair and invalidating the old refresh token
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, *model.User, error)
+	// Logout revokes the access token and, when given, the refresh token of the same user
+	Logout(ctx context.Context, claims *jwt.Claims, refreshToken string) error
+	// RevokeUser invalidates every access and refresh token issued to the user so far
+	RevokeUser(ctx context.Context, userID uint) error
+	IsRevoked(ctx context.Context, claims *jwt.Claims) (bool, error)
+}
+
+type tokenService struct {
+	tokenRepo  repository.TokenRepository
+	userRepo   repository.UserRepository
+	roleRepo   repository.RoleRepository
+	jwtManager *jwt.JWTManager
+}
+
+func NewTokenService(tokenRepo repository.TokenRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository, jwtManager *jwt.JWTManager) TokenService {
+	return &tokenService{
+		tokenRepo:  tokenRepo,
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		jwtManager: jwtManager,
+	}
+}
+
+func (s *tokenService) Issue(ctx context.Context, user *model.User) (*TokenPair, error) {
+	role, err := s.roleRepo.GetByName(ctx, user.Role)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+
+	accessToken, err := s.jwtManager.GenerateToken(user.ID, user.Username, user.Role, role.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshExpiresAt, err := s.jwtManager.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.CreateRefreshToken(ctx, &model.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: jwt.HashToken(refreshToken),
+		ExpiresAt: refreshExpiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().Add(s.jwtManager.TokenDuration()),
+		Permissions:  role.Permissions,
+	}, nil
+}
+
+func (s *tokenService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, *model.User, error) {
+	stored, err := s.tokenRepo.GetRefreshTokenByHash(ctx, jwt.HashToken(refreshToken))
+	if err != nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if !stored.ExpiresAt.After(time.Now()) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	// A refresh token is single use; seeing it twice means it leaked, so cut off the whole session
+	rotated, err := s.tokenRepo.RevokeRefreshToken(ctx, stored.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rotated {
+		if err := s.RevokeUser(ctx, stored.UserID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil || !user.Active {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	pair, err := s.Issue(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
+
+func (s *tokenService) Logout(ctx context.Context, claims *jwt.Claims, refreshToken string) error {
+	if err := s.tokenRepo.CreateRevokedToken(ctx, &model.RevokedToken{
+		JTI:       claims.ID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}); err != nil {
+		return err
+	}
+
+	if refreshToken != "" {
+		stored, err := s.tokenRepo.GetRefreshTokenByHash(ctx, jwt.HashToken(refreshToken))
+		if err == nil && stored.UserID == claims.UserID {
+			if _, err := s.tokenRepo.RevokeRefreshToken(ctx, stored.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Housekeeping; a failure here must not fail the logout
+	if err := s.tokenRepo.DeleteExpired(ctx, time.Now()); err != nil {
+		log.Printf("token cleanup failed: %v", err)
+	}
+	return nil
+}
+
+func (s *tokenService) RevokeUser(ctx context.Context, userID uint) error {
+	if err := s.tokenRepo.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+	return s.tokenRepo.CreateRevokedToken(ctx, &model.RevokedToken{
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(s.jwtManager.TokenDuration()),
+	})
+}
+
+func (s *tokenService) IsRevoked(ctx context.Context, claims *jwt.Claims) (bool, error) {
+	return s.tokenRepo.IsRevoked(ctx, claims.ID, claims.UserID, claims.Issued())
+}
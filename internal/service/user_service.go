@@ -2,39 +2,208 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base32"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"absence/internal/model"
 	"absence/internal/repository"
+	"absence/pkg/jwt"
+	"absence/pkg/notifier"
+	"absence/pkg/totp"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUserInactive       = errors.New("account is deactivated")
+	ErrUserNotDeleted     = errors.New("user is not deleted")
+	ErrInvalidPassword    = errors.New("current password is incorrect")
+	ErrInvalidResetToken  = errors.New("invalid or expired password reset token")
+
+	ErrRegistrationClosed    = errors.New("self-registration is closed, ask an administrator for an invitation")
+	ErrEmailDomainNotAllowed = errors.New("registration is not open for this email domain")
+	ErrUsernameTaken         = errors.New("username is already taken")
+	ErrEmailTaken            = errors.New("email is already registered")
+
+	ErrTOTPAlreadyEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrTOTPNotSetUp          = errors.New("two-factor authentication has not been set up")
+	ErrTOTPNotEnabled        = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode  = errors.New("invalid two-factor code")
+	ErrInvalidLoginChallenge = errors.New("invalid or expired login challenge")
+)
+
+const (
+	recoveryCodeCount = 10
+	// totpSkew accepts codes from one period before and after the current one to allow for clock drift
+	totpSkew = 1
+	// maxChallengeAttempts is how many codes may be tried against one login challenge
+	maxChallengeAttempts = 5
+)
+
+// PasswordResetConfig controls the forgot-password flow
+type PasswordResetConfig struct {
+	// TokenTTL is how long a reset token stays valid
+	TokenTTL time.Duration
+	// URL is the page users open to choose a new password; the token is appended as ?token=
+	URL string
+}
+
+// RegistrationConfig controls who can create an account
+type RegistrationConfig struct {
+	// Open allows self-registration; such accounts always get the employee role
+	Open bool
+	// AllowedDomains restricts open registration to these email domains; empty allows any domain
+	AllowedDomains []string
+	// InvitationTTL is how long an invitation can be redeemed
+	InvitationTTL time.Duration
+	// InvitationURL is the page invitees open to create their account; the token is appended as ?token=
+	InvitationURL string
+}
+
+// allowsEmail reports whether open registration accepts the email address
+func (c *RegistrationConfig) allowsEmail(email string) bool {
+	if len(c.AllowedDomains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range c.AllowedDomains {
+		if domain == strings.ToLower(allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// TwoFactorConfig controls TOTP enrollment and the second login step
+type TwoFactorConfig struct {
+	// Issuer is the name authenticator apps show next to the account
+	Issuer string
+	// ChallengeTTL is how long a login challenge waits for its code
+	ChallengeTTL time.Duration
+}
+
+// TOTPSetup is what a user needs to add the account to an authenticator app
+type TOTPSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorStatus describes a user's two-factor authentication
+type TwoFactorStatus struct {
+	Enabled                bool  `json:"enabled"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// LoginChallenge is returned by a password login that still needs a second factor
+type LoginChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
 type UserService interface {
+	// Register creates an employee account when open self-registration allows it
 	Register(ctx context.Context, user *model.User) error
 	Login(ctx context.Context, username, password string) (*model.User, error)
 	GetByID(ctx context.Context, id uint) (*model.User, error)
+	GetWithEmployeeDetail(ctx context.Context, id uint) (*model.User, error)
+	// List returns one page of users matching the filter and the number of matching users
+	List(ctx context.Context, filter repository.UserFilter) ([]model.User, int64, error)
 	Update(ctx context.Context, user *model.User) error
+	// Delete soft-deletes the user, keeping their attendance and leave history
 	Delete(ctx context.Context, id uint) error
+	// Restore brings back a deleted user
+	Restore(ctx context.Context, id uint) (*model.User, error)
+	// SetActive activates or deactivates a user; inactive users cannot log in
+	SetActive(ctx context.Context, id uint, active bool) (*model.User, error)
+	// ChangePassword replaces the user's password after verifying the current one
+	ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error
+	// ResetPassword sets a new password without knowing the old one (admin action)
+	ResetPassword(ctx context.Context, userID uint, newPassword string) error
+	// RequestPasswordReset sends a reset link to the user with the given email, if any
+	RequestPasswordReset(ctx context.Context, email string) error
+	// ConfirmPasswordReset consumes a reset token and sets the new password, returning the user
+	ConfirmPasswordReset(ctx context.Context, token, newPassword string) (*model.User, error)
+	GetTwoFactorStatus(ctx context.Context, userID uint) (*TwoFactorStatus, error)
+	// SetupTOTP generates a new secret; it only takes effect once confirmed with EnableTOTP
+	SetupTOTP(ctx context.Context, userID uint) (*TOTPSetup, error)
+	// EnableTOTP verifies a code for the pending secret and returns freshly generated recovery codes
+	EnableTOTP(ctx context.Context, userID uint, code string) ([]string, error)
+	// DisableTOTP turns two-factor authentication off after checking the password and a code
+	DisableTOTP(ctx context.Context, userID uint, password, code string) error
+	// RegenerateRecoveryCodes replaces all recovery codes after checking a code
+	RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error)
+	// ResetTOTP turns two-factor authentication off without any code (admin action for lost devices)
+	ResetTOTP(ctx context.Context, userID uint) error
+	// CreateLoginChallenge starts the second login step for a user whose password was verified
+	CreateLoginChallenge(ctx context.Context, user *model.User) (*LoginChallenge, error)
+	// GetLoginChallengeUser returns the user a still usable login challenge belongs to
+	GetLoginChallengeUser(ctx context.Context, challenge string) (*model.User, error)
+	// CompleteLoginChallenge consumes the challenge when the TOTP or recovery code is valid
+	CompleteLoginChallenge(ctx context.Context, challenge, code string) error
 }
 
 type UserServiceImpl struct {
-	userRepo repository.UserRepository
+	userRepo           repository.UserRepository
+	resetRepo          repository.PasswordResetRepository
+	twoFactorRepo      repository.TwoFactorRepository
+	notifier           notifier.Notifier
+	resetConfig        *PasswordResetConfig
+	twoFactorConfig    *TwoFactorConfig
+	registrationConfig *RegistrationConfig
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
+func NewUserService(
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetRepository,
+	twoFactorRepo repository.TwoFactorRepository,
+	notifier notifier.Notifier,
+	resetConfig *PasswordResetConfig,
+	twoFactorConfig *TwoFactorConfig,
+	registrationConfig *RegistrationConfig,
+) UserService {
 	return &UserServiceImpl{
-		userRepo: userRepo,
+		userRepo:           userRepo,
+		resetRepo:          resetRepo,
+		twoFactorRepo:      twoFactorRepo,
+		notifier:           notifier,
+		resetConfig:        resetConfig,
+		twoFactorConfig:    twoFactorConfig,
+		registrationConfig: registrationConfig,
 	}
 }
 
 func (s *UserServiceImpl) Register(ctx context.Context, user *model.User) error {
+	if !s.registrationConfig.Open {
+		return ErrRegistrationClosed
+	}
+	if !s.registrationConfig.allowsEmail(user.Email) {
+		return ErrEmailDomainNotAllowed
+	}
+	if err := checkAvailable(ctx, s.userRepo, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	// Anything beyond the employee role has to be granted by an administrator
+	user.Role = model.RoleEmployee
+	user.DepartmentID = nil
+	user.Active = true
+
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := hashPassword(user.Password)
 	if err != nil {
 		return err
 	}
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 
 	return s.userRepo.Create(ctx, user)
 }
@@ -42,13 +211,18 @@ func (s *UserServiceImpl) Register(ctx context.Context, user *model.User) error
 func (s *UserServiceImpl) Login(ctx context.Context, username, password string) (*model.User, error) {
 	user, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, ErrInvalidCredentials
 	}
 
 	// Compare passwords
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, ErrInvalidCredentials
+	}
+
+	// Only reveal the account state to someone who knows the password
+	if !user.Active {
+		return nil, ErrUserInactive
 	}
 
 	return user, nil
@@ -58,10 +232,427 @@ func (s *UserServiceImpl) GetByID(ctx context.Context, id uint) (*model.User, er
 	return s.userRepo.GetByID(ctx, id)
 }
 
+func (s *UserServiceImpl) GetWithEmployeeDetail(ctx context.Context, id uint) (*model.User, error) {
+	return s.userRepo.GetByIDWithEmployeeDetail(ctx, id)
+}
+
+func (s *UserServiceImpl) List(ctx context.Context, filter repository.UserFilter) ([]model.User, int64, error) {
+	return s.userRepo.List(ctx, filter)
+}
+
 func (s *UserServiceImpl) Update(ctx context.Context, user *model.User) error {
-	return s.userRepo.Update(ctx, user)
+	existing, err := s.userRepo.GetByID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	// Only profile fields are editable here; password, role and department have their own flows
+	existing.Username = user.Username
+	existing.FullName = user.FullName
+	existing.Email = user.Email
+
+	if err := s.userRepo.Update(ctx, existing); err != nil {
+		return err
+	}
+	*user = *existing
+	return nil
 }
 
 func (s *UserServiceImpl) Delete(ctx context.Context, id uint) error {
+	if _, err := s.userRepo.GetByID(ctx, id); err != nil {
+		return ErrUserNotFound
+	}
 	return s.userRepo.Delete(ctx, id)
 }
+
+func (s *UserServiceImpl) Restore(ctx context.Context, id uint) (*model.User, error) {
+	if _, err := s.userRepo.GetByID(ctx, id); err == nil {
+		return nil, ErrUserNotDeleted
+	}
+
+	restored, err := s.userRepo.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !restored {
+		return nil, ErrUserNotFound
+	}
+	return s.userRepo.GetByID(ctx, id)
+}
+
+func (s *UserServiceImpl) SetActive(ctx context.Context, id uint, active bool) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.userRepo.SetActive(ctx, id, active); err != nil {
+		return nil, err
+	}
+	user.Active = active
+	return user, nil
+}
+
+func (s *UserServiceImpl) ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
+		return ErrInvalidPassword
+	}
+	return s.setPassword(ctx, user, newPassword)
+}
+
+func (s *UserServiceImpl) ResetPassword(ctx context.Context, userID uint, newPassword string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	return s.setPassword(ctx, user, newPassword)
+}
+
+func (s *UserServiceImpl) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || !user.Active {
+		// Do not reveal whether the email is registered
+		return nil
+	}
+
+	token, err := jwt.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	if err := s.resetRepo.Create(ctx, &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: jwt.HashToken(token),
+		ExpiresAt: time.Now().Add(s.resetConfig.TokenTTL),
+	}); err != nil {
+		return err
+	}
+
+	link := token
+	if s.resetConfig.URL != "" {
+		link = s.resetConfig.URL + "?token=" + token
+	}
+	msg := notifier.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the following link to choose a new password. It expires in %s and works once.\n\n%s\n\nIf you did not ask for this, you can ignore this message.",
+			user.FullName, s.resetConfig.TokenTTL, link),
+	}
+	if err := s.notifier.Send(ctx, msg); err != nil {
+		log.Printf("failed to send password reset to user %d: %v", user.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *UserServiceImpl) ConfirmPasswordReset(ctx context.Context, token, newPassword string) (*model.User, error) {
+	reset, err := s.resetRepo.GetByHash(ctx, jwt.HashToken(token))
+	if err != nil || reset.UsedAt != nil || !reset.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidResetToken
+	}
+
+	// Consume the token first so two concurrent requests cannot both use it
+	used, err := s.resetRepo.MarkUsed(ctx, reset.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, reset.UserID)
+	if err != nil {
+		return nil, ErrInvalidResetToken
+	}
+	if err := s.setPassword(ctx, user, newPassword); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// setPassword hashes and stores a new password and voids any reset links still outstanding
+func (s *UserServiceImpl) setPassword(ctx context.Context, user *model.User, password string) error {
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	user.Password = hashedPassword
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	return s.resetRepo.InvalidateUser(ctx, user.ID)
+}
+
+func (s *UserServiceImpl) GetTwoFactorStatus(ctx context.Context, userID uint) (*TwoFactorStatus, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	status := &TwoFactorStatus{Enabled: user.TOTPEnabled}
+	if user.TOTPEnabled {
+		if status.RecoveryCodesRemaining, err = s.twoFactorRepo.CountUnusedRecoveryCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func (s *UserServiceImpl) SetupTOTP(ctx context.Context, userID uint) (*TOTPSetup, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateTOTP(ctx, userID, secret, false); err != nil {
+		return nil, err
+	}
+
+	return &TOTPSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.twoFactorConfig.Issuer, user.Username, secret),
+	}, nil
+}
+
+func (s *UserServiceImpl) EnableTOTP(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTOTPNotSetUp
+	}
+	if err := s.verifyTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateTOTP(ctx, userID, user.TOTPSecret, true); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *UserServiceImpl) DisableTOTP(ctx context.Context, userID uint, password, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if !user.TOTPEnabled {
+		return ErrTOTPNotEnabled
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return ErrInvalidPassword
+	}
+	if err := s.verifySecondFactor(ctx, user, code); err != nil {
+		return err
+	}
+	return s.ResetTOTP(ctx, userID)
+}
+
+func (s *UserServiceImpl) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.TOTPEnabled {
+		return nil, ErrTOTPNotEnabled
+	}
+	if err := s.verifySecondFactor(ctx, user, code); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+func (s *UserServiceImpl) ResetTOTP(ctx context.Context, userID uint) error {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return ErrUserNotFound
+	}
+	if err := s.userRepo.UpdateTOTP(ctx, userID, "", false); err != nil {
+		return err
+	}
+	return s.twoFactorRepo.DeleteRecoveryCodes(ctx, userID)
+}
+
+func (s *UserServiceImpl) CreateLoginChallenge(ctx context.Context, user *model.User) (*LoginChallenge, error) {
+	token, err := jwt.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.twoFactorConfig.ChallengeTTL)
+	if err := s.twoFactorRepo.CreateChallenge(ctx, &model.LoginChallenge{
+		UserID:    user.ID,
+		TokenHash: jwt.HashToken(token),
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+	return &LoginChallenge{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+func (s *UserServiceImpl) GetLoginChallengeUser(ctx context.Context, challenge string) (*model.User, error) {
+	_, user, err := s.loginChallenge(ctx, challenge)
+	return user, err
+}
+
+func (s *UserServiceImpl) CompleteLoginChallenge(ctx context.Context, challenge, code string) error {
+	stored, user, err := s.loginChallenge(ctx, challenge)
+	if err != nil {
+		return err
+	}
+
+	counted, err := s.twoFactorRepo.AddChallengeAttempt(ctx, stored.ID, maxChallengeAttempts)
+	if err != nil {
+		return err
+	}
+	if !counted {
+		return ErrInvalidLoginChallenge
+	}
+
+	if err := s.verifySecondFactor(ctx, user, code); err != nil {
+		return err
+	}
+
+	consumed, err := s.twoFactorRepo.ConsumeChallenge(ctx, stored.ID)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidLoginChallenge
+	}
+	return nil
+}
+
+// loginChallenge looks up a challenge that is unused, unexpired, has attempts left and whose user still has 2FA on
+func (s *UserServiceImpl) loginChallenge(ctx context.Context, challenge string) (*model.LoginChallenge, *model.User, error) {
+	stored, err := s.twoFactorRepo.GetChallengeByHash(ctx, jwt.HashToken(challenge))
+	if err != nil || stored.UsedAt != nil || stored.Attempts >= maxChallengeAttempts || !stored.ExpiresAt.After(time.Now()) {
+		return nil, nil, ErrInvalidLoginChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil || !user.TOTPEnabled || !user.Active {
+		return nil, nil, ErrInvalidLoginChallenge
+	}
+	return stored, user, nil
+}
+
+// verifySecondFactor accepts either a current TOTP code or an unused recovery code
+func (s *UserServiceImpl) verifySecondFactor(ctx context.Context, user *model.User, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return s.verifyTOTP(ctx, user, code)
+	}
+
+	used, err := s.twoFactorRepo.UseRecoveryCode(ctx, user.ID, jwt.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// verifyTOTP checks a code against the user's secret and refuses codes from an already used time step
+func (s *UserServiceImpl) verifyTOTP(ctx context.Context, user *model.User, code string) error {
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	fresh, err := s.userRepo.AdvanceTOTPStep(ctx, user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// replaceRecoveryCodes generates a new set of recovery codes, storing only their hashes
+func (s *UserServiceImpl) replaceRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	records := make([]model.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		records[i] = model.RecoveryCode{UserID: userID, CodeHash: jwt.HashToken(normalizeRecoveryCode(code))}
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// newRecoveryCode returns a random code formatted as two groups of five characters, e.g. "k3v9q-7hx2m"
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 7)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode ignores case, spaces and dashes so codes can be typed in however they were written down
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+func hashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// checkAvailable reports a clear error instead of a unique constraint violation when the username or
+// email is in use, including by a deleted user
+func checkAvailable(ctx context.Context, userRepo repository.UserRepository, username, email string) error {
+	if username != "" {
+		taken, err := userRepo.UsernameExists(ctx, username)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrUsernameTaken
+		}
+	}
+
+	taken, err := userRepo.EmailExists(ctx, email)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrEmailTaken
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+	"absence/pkg/totp"
+
+	"gorm.io/gorm"
+)
+
+type userRepositoryStub struct {
+	repository.UserRepository
+	users map[uint]model.User
+}
+
+func (r *userRepositoryStub) GetByID(ctx context.Context, id uint) (*model.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+func (r *userRepositoryStub) AdvanceTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	user := r.users[userID]
+	if user.TOTPLastStep >= step {
+		return false, nil
+	}
+	user.TOTPLastStep = step
+	r.users[userID] = user
+	return true, nil
+}
+
+func TestVerifyTOTPRejectsUsedSteps(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user := model.User{ID: 1, TOTPSecret: secret, TOTPEnabled: true}
+	svc := &UserServiceImpl{userRepo: &userRepositoryStub{users: map[uint]model.User{1: user}}}
+
+	current := totp.Step(time.Now())
+	codeAt := func(step int64) string {
+		code, err := totp.Code(secret, step)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return code
+	}
+
+	if err := svc.verifyTOTP(context.Background(), &user, codeAt(current-1)); err != nil {
+		t.Fatalf("expected the previous step to be accepted, got %v", err)
+	}
+	if err := svc.verifyTOTP(context.Background(), &user, codeAt(current-1)); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("expected a replayed code to be rejected, got %v", err)
+	}
+	if err := svc.verifyTOTP(context.Background(), &user, codeAt(current)); err != nil {
+		t.Fatalf("expected the current step to be accepted, got %v", err)
+	}
+	if err := svc.verifyTOTP(context.Background(), &user, codeAt(current-1)); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("expected a code older than the last used step to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"absence/internal/model"
+	"absence/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrScheduleNotFound  = errors.New("work schedule not found")
+	ErrInvalidDayOfWeek  = errors.New("day of week must be between 1 (Monday) and 7 (Sunday)")
+	ErrInvalidClockTime  = errors.New("time must be formatted as HH:MM or HH:MM:SS")
+	ErrEmptyScheduleSpan = errors.New("start and end time must differ")
+)
+
+type WorkScheduleService interface {
+	GetDepartmentSchedules(ctx context.Context, departmentID uint) ([]model.WorkSchedule, error)
+	// SaveSchedule creates or replaces the department's schedule for schedule.DayOfWeek
+	SaveSchedule(ctx context.Context, schedule *model.WorkSchedule) error
+	DeleteSchedule(ctx context.Context, departmentID uint, dayOfWeek int) error
+	// GetUserSchedule returns the schedule that applies to the user on the given day, or nil when none does
+	GetUserSchedule(ctx context.Context, userID uint, day time.Time) (*model.WorkSchedule, error)
+}
+
+type workScheduleService struct {
+	scheduleRepo   repository.WorkScheduleRepository
+	departmentRepo repository.DepartmentRepository
+	userRepo       repository.UserRepository
+}
+
+func NewWorkScheduleService(
+	scheduleRepo repository.WorkScheduleRepository,
+	departmentRepo repository.DepartmentRepository,
+	userRepo repository.UserRepository,
+) WorkScheduleService {
+	return &workScheduleService{
+		scheduleRepo:   scheduleRepo,
+		departmentRepo: departmentRepo,
+		userRepo:       userRepo,
+	}
+}
+
+func (s *workScheduleService) GetDepartmentSchedules(ctx context.Context, departmentID uint) ([]model.WorkSchedule, error) {
+	if _, err := s.departmentRepo.GetByID(ctx, departmentID); err != nil {
+		return nil, ErrDepartmentNotFound
+	}
+	return s.scheduleRepo.GetByDepartment(ctx, departmentID)
+}
+
+func (s *workScheduleService) SaveSchedule(ctx context.Context, schedule *model.WorkSchedule) error {
+	if schedule.DayOfWeek < 1 || schedule.DayOfWeek > 7 {
+		return ErrInvalidDayOfWeek
+	}
+
+	start, err := normalizeClock(schedule.StartTime)
+	if err != nil {
+		return err
+	}
+	end, err := normalizeClock(schedule.EndTime)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return ErrEmptyScheduleSpan
+	}
+	schedule.StartTime = start
+	schedule.EndTime = end
+
+	if _, err := s.departmentRepo.GetByID(ctx, schedule.DepartmentID); err != nil {
+		return ErrDepartmentNotFound
+	}
+
+	existing, err := s.scheduleRepo.GetByDepartmentAndDay(ctx, schedule.DepartmentID, schedule.DayOfWeek)
+	if err != nil {
+		return s.scheduleRepo.Create(ctx, schedule)
+	}
+
+	existing.StartTime = schedule.StartTime
+	existing.EndTime = schedule.EndTime
+	existing.GraceMinutes = schedule.GraceMinutes
+	if err := s.scheduleRepo.Update(ctx, existing); err != nil {
+		return err
+	}
+	*schedule = *existing
+	return nil
+}
+
+func (s *workScheduleService) DeleteSchedule(ctx context.Context, departmentID uint, dayOfWeek int) error {
+	schedule, err := s.scheduleRepo.GetByDepartmentAndDay(ctx, departmentID, dayOfWeek)
+	if err != nil {
+		return ErrScheduleNotFound
+	}
+	return s.scheduleRepo.Delete(ctx, schedule.ID)
+}
+
+func (s *workScheduleService) GetUserSchedule(ctx context.Context, userID uint, day time.Time) (*model.WorkSchedule, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DepartmentID == nil {
+		return nil, nil
+	}
+
+	schedule, err := s.scheduleRepo.GetByDepartmentAndDay(ctx, *user.DepartmentID, isoWeekday(day))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return schedule, err
+}
+
+// isoWeekday converts Go's Sunday-first weekday to ISO 8601 numbering (Monday=1 .. Sunday=7)
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
+
+// normalizeClock validates an HH:MM or HH:MM:SS string and returns it as HH:MM:SS
+func normalizeClock(value string) (string, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("15:04:05"), nil
+		}
+	}
+	return "", ErrInvalidClockTime
+}
+
+// scheduleWindow resolves the schedule's start and end on the calendar day of day.
+// Schedules whose end is not after their start run past midnight into the next day.
+func scheduleWindow(schedule *model.WorkSchedule, day time.Time) (time.Time, time.Time, error) {
+	clock := func(value string) (time.Time, error) {
+		// Databases may return TIME columns with fractional seconds; only HH:MM:SS matters
+		if len(value) > 8 {
+			value = value[:8]
+		}
+		normalized, err := normalizeClock(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("schedule %d: %w", schedule.ID, err)
+		}
+		t, _ := time.Parse("15:04:05", normalized)
+		return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, day.Location()), nil
+	}
+
+	start, err := clock(schedule.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := clock(schedule.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !end.After(start) {
+		end = end.Add(24 * time.Hour)
+	}
+	return start, end, nil
+}
+
+// classifyCheckIn reports whether checkIn is on time for the schedule of day and, if late, by how many minutes
+func classifyCheckIn(schedule *model.WorkSchedule, day, checkIn time.Time) (string, int, error) {
+	if schedule == nil {
+		return model.ScheduleUnscheduled, 0, nil
+	}
+
+	start, _, err := scheduleWindow(schedule, day)
+	if err != nil {
+		return "", 0, err
+	}
+
+	deadline := start.Add(time.Duration(schedule.GraceMinutes) * time.Minute)
+	if !checkIn.After(deadline) {
+		return model.ScheduleOnTime, 0, nil
+	}
+	return model.ScheduleLate, int(checkIn.Sub(start) / time.Minute), nil
+}
+
+// classifyCheckOut reports whether checkOut happened before the end of the shift of day
+func classifyCheckOut(schedule *model.WorkSchedule, day, checkOut time.Time) (string, int, error) {
+	if schedule == nil {
+		return model.ScheduleUnscheduled, 0, nil
+	}
+
+	_, end, err := scheduleWindow(schedule, day)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if !checkOut.Before(end) {
+		return model.ScheduleOnTime, 0, nil
+	}
+	return model.ScheduleEarlyLeave, int(end.Sub(checkOut) / time.Minute), nil
+}
+
+// unclassified is the classification of a check-in or check-out the schedule does not apply to,
+// such as the check-in of a second session on the same day
+func unclassified(schedule *model.WorkSchedule) string {
+	if schedule == nil {
+		return model.ScheduleUnscheduled
+	}
+	return model.ScheduleOnTime
+}
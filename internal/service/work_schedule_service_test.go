@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"absence/internal/model"
+)
+
+func TestClassifyCheckIn(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute, second int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second)
+	}
+	daytime := &model.WorkSchedule{StartTime: "09:00:00", EndTime: "17:00:00", GraceMinutes: 10}
+	overnight := &model.WorkSchedule{StartTime: "22:00:00", EndTime: "06:00:00", GraceMinutes: 5}
+
+	tests := []struct {
+		name     string
+		schedule *model.WorkSchedule
+		checkIn  time.Time
+		status   string
+		late     int
+	}{
+		{name: "no schedule", checkIn: at(9, 30, 0), status: model.ScheduleUnscheduled},
+		{name: "early", schedule: daytime, checkIn: at(8, 30, 0), status: model.ScheduleOnTime},
+		{name: "at the start", schedule: daytime, checkIn: at(9, 0, 0), status: model.ScheduleOnTime},
+		{name: "at the end of the grace period", schedule: daytime, checkIn: at(9, 10, 0), status: model.ScheduleOnTime},
+		{name: "a second after the grace period", schedule: daytime, checkIn: at(9, 10, 1), status: model.ScheduleLate, late: 10},
+		{name: "a minute after the grace period", schedule: daytime, checkIn: at(9, 11, 0), status: model.ScheduleLate, late: 11},
+		{name: "no grace period", schedule: &model.WorkSchedule{StartTime: "09:00", EndTime: "17:00"}, checkIn: at(9, 0, 1), status: model.ScheduleLate},
+		{name: "fractional seconds from the database", schedule: &model.WorkSchedule{StartTime: "09:00:00.000000", EndTime: "17:00:00.000000", GraceMinutes: 10}, checkIn: at(9, 10, 0), status: model.ScheduleOnTime},
+		{name: "overnight at the end of the grace period", schedule: overnight, checkIn: at(22, 5, 0), status: model.ScheduleOnTime},
+		{name: "overnight after the grace period", schedule: overnight, checkIn: at(22, 6, 0), status: model.ScheduleLate, late: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, late, err := classifyCheckIn(tt.schedule, day, tt.checkIn)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status != tt.status || late != tt.late {
+				t.Errorf("expected (%s, %d), got (%s, %d)", tt.status, tt.late, status, late)
+			}
+		})
+	}
+}
+
+func TestClassifyCheckOut(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	daytime := &model.WorkSchedule{StartTime: "09:00:00", EndTime: "17:00:00", GraceMinutes: 10}
+	overnight := &model.WorkSchedule{StartTime: "22:00:00", EndTime: "06:00:00", GraceMinutes: 5}
+
+	tests := []struct {
+		name     string
+		schedule *model.WorkSchedule
+		checkOut time.Time
+		status   string
+		early    int
+	}{
+		{name: "no schedule", checkOut: at(15, 0), status: model.ScheduleUnscheduled},
+		{name: "at the end", schedule: daytime, checkOut: at(17, 0), status: model.ScheduleOnTime},
+		{name: "after the end", schedule: daytime, checkOut: at(18, 30), status: model.ScheduleOnTime},
+		{name: "a minute early", schedule: daytime, checkOut: at(16, 59), status: model.ScheduleEarlyLeave, early: 1},
+		{name: "grace period does not apply", schedule: daytime, checkOut: at(16, 55), status: model.ScheduleEarlyLeave, early: 5},
+		{name: "overnight at the end on the next day", schedule: overnight, checkOut: at(30, 0), status: model.ScheduleOnTime},
+		{name: "overnight before midnight", schedule: overnight, checkOut: at(23, 30), status: model.ScheduleEarlyLeave, early: 390},
+		{name: "overnight a minute early", schedule: overnight, checkOut: at(29, 59), status: model.ScheduleEarlyLeave, early: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, early, err := classifyCheckOut(tt.schedule, day, tt.checkOut)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status != tt.status || early != tt.early {
+				t.Errorf("expected (%s, %d), got (%s, %d)", tt.status, tt.early, status, early)
+			}
+		})
+	}
+}
+
+func TestClassifyRejectsInvalidSchedules(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	schedule := &model.WorkSchedule{ID: 3, StartTime: "9am", EndTime: "17:00:00"}
+
+	if _, _, err := classifyCheckIn(schedule, day, day.Add(9*time.Hour)); err == nil {
+		t.Error("expected classifyCheckIn to reject an invalid start time")
+	}
+	if _, _, err := classifyCheckOut(schedule, day, day.Add(17*time.Hour)); err == nil {
+		t.Error("expected classifyCheckOut to reject an invalid start time")
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"absence/internal/model"
+)
+
+// OvertimeRules configure how worked time is split into regular time and overtime
+type OvertimeRules struct {
+	// DailyThreshold is the regular time of a workday, summed over its sessions; 0 disables the limit
+	DailyThreshold time.Duration
+	// WeeklyThreshold is the regular time of an ISO week (Monday to Sunday); 0 disables the limit
+	WeeklyThreshold time.Duration
+	// Work on weekends and holidays is all overtime, weighted with these multipliers
+	WeekendMultiplier float64
+	HolidayMultiplier float64
+}
+
+// WorkedHours adds up the worked time of a period
+type WorkedHours struct {
+	WorkedMinutes   int `json:"worked_minutes"`
+	BreakMinutes    int `json:"break_minutes"`
+	RegularMinutes  int `json:"regular_minutes"`
+	OvertimeMinutes int `json:"overtime_minutes"`
+	// WeightedMinutes counts overtime on weekends and holidays with their multiplier
+	WeightedMinutes int `json:"weighted_minutes"`
+}
+
+// calculateHours derives the regular, overtime and weighted minutes of a user's sessions from their
+// net worked minutes. The sessions must be ordered by workday and check-in and include every earlier
+// session of the first week, since those count towards the weekly threshold.
+func calculateHours(rules OvertimeRules, sessions []model.Attendance) {
+	dailyLimit := int(rules.DailyThreshold / time.Minute)
+	weeklyLimit := int(rules.WeeklyThreshold / time.Minute)
+
+	var day, week time.Time
+	dayRegular, weekRegular := 0, 0
+	for i := range sessions {
+		session := &sessions[i]
+		session.RegularMinutes, session.OvertimeMinutes, session.WeightedMinutes = 0, 0, 0
+		if session.WorkedMinutes <= 0 {
+			continue
+		}
+
+		if !session.Date.Equal(day) {
+			day, dayRegular = session.Date, 0
+		}
+		if start := startOfWeek(session.Date); !start.Equal(week) {
+			week, weekRegular = start, 0
+		}
+
+		regular, multiplier := session.WorkedMinutes, 1.0
+		switch {
+		case session.HolidayOvertime:
+			regular, multiplier = 0, rules.HolidayMultiplier
+		case isoWeekday(session.Date) >= 6:
+			regular, multiplier = 0, rules.WeekendMultiplier
+		default:
+			if dailyLimit > 0 {
+				regular = min(regular, max(dailyLimit-dayRegular, 0))
+			}
+			if weeklyLimit > 0 {
+				regular = min(regular, max(weeklyLimit-weekRegular, 0))
+			}
+		}
+		dayRegular += regular
+		weekRegular += regular
+
+		overtime := session.WorkedMinutes - regular
+		session.RegularMinutes = regular
+		session.OvertimeMinutes = overtime
+		session.WeightedMinutes = regular + int(math.Round(float64(overtime)*multiplier))
+	}
+}
+
+// sumHours adds up the worked time of sessions passed through calculateHours
+func sumHours(sessions []model.Attendance) WorkedHours {
+	var total WorkedHours
+	for _, session := range sessions {
+		total.WorkedMinutes += session.WorkedMinutes
+		total.BreakMinutes += session.BreakMinutes
+		total.RegularMinutes += session.RegularMinutes
+		total.OvertimeMinutes += session.OvertimeMinutes
+		total.WeightedMinutes += session.WeightedMinutes
+	}
+	return total
+}
+
+// startOfWeek returns local midnight of the Monday of t's ISO week
+func startOfWeek(t time.Time) time.Time {
+	return startOfDay(t).AddDate(0, 0, 1-isoWeekday(t))
+}
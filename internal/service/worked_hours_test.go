@@ -0,0 +1,136 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"absence/internal/model"
+)
+
+func TestCalculateHours(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	day := func(offset int) time.Time {
+		return monday.AddDate(0, 0, offset)
+	}
+	// session is checked in on the day at the given hour, which may make it end after midnight
+	session := func(date time.Time, hour, worked int) model.Attendance {
+		checkIn := date.Add(time.Duration(hour) * time.Hour)
+		checkOut := checkIn.Add(time.Duration(worked) * time.Minute)
+		return model.Attendance{Date: date, CheckIn: &checkIn, CheckOut: &checkOut, WorkedMinutes: worked}
+	}
+	holiday := func(s model.Attendance) model.Attendance {
+		s.HolidayOvertime = true
+		return s
+	}
+
+	rules := OvertimeRules{
+		DailyThreshold:    8 * time.Hour,
+		WeeklyThreshold:   40 * time.Hour,
+		WeekendMultiplier: 1.5,
+		HolidayMultiplier: 2,
+	}
+	// minutes holds the regular, overtime and weighted minutes of a session
+	type minutes struct{ regular, overtime, weighted int }
+
+	tests := []struct {
+		name     string
+		rules    OvertimeRules
+		sessions []model.Attendance
+		expected []minutes
+	}{
+		{
+			name:     "below the daily threshold",
+			rules:    rules,
+			sessions: []model.Attendance{session(day(0), 9, 420)},
+			expected: []minutes{{420, 0, 420}},
+		},
+		{
+			name:     "crossing the daily threshold in the second session",
+			rules:    rules,
+			sessions: []model.Attendance{session(day(0), 8, 300), session(day(0), 14, 300)},
+			expected: []minutes{{300, 0, 300}, {180, 120, 300}},
+		},
+		{
+			name:  "crossing the weekly threshold mid-day",
+			rules: OvertimeRules{DailyThreshold: 8 * time.Hour, WeeklyThreshold: 36 * time.Hour, WeekendMultiplier: 1.5, HolidayMultiplier: 2},
+			sessions: []model.Attendance{
+				session(day(0), 9, 480), session(day(1), 9, 480), session(day(2), 9, 480),
+				session(day(3), 9, 480), session(day(4), 9, 480),
+			},
+			expected: []minutes{{480, 0, 480}, {480, 0, 480}, {480, 0, 480}, {480, 0, 480}, {240, 240, 480}},
+		},
+		{
+			name:  "weekly threshold resets on monday",
+			rules: OvertimeRules{WeeklyThreshold: 8 * time.Hour, WeekendMultiplier: 1.5, HolidayMultiplier: 2},
+			sessions: []model.Attendance{
+				session(day(3), 9, 480), session(day(4), 9, 60), session(day(7), 9, 480),
+			},
+			expected: []minutes{{480, 0, 480}, {0, 60, 60}, {480, 0, 480}},
+		},
+		{
+			name:     "weekend",
+			rules:    rules,
+			sessions: []model.Attendance{session(day(6), 10, 120)},
+			expected: []minutes{{0, 120, 180}},
+		},
+		{
+			name:     "holiday on a weekday",
+			rules:    rules,
+			sessions: []model.Attendance{holiday(session(day(2), 10, 120))},
+			expected: []minutes{{0, 120, 240}},
+		},
+		{
+			name:     "holiday on a weekend takes the holiday multiplier",
+			rules:    rules,
+			sessions: []model.Attendance{holiday(session(day(5), 10, 120))},
+			expected: []minutes{{0, 120, 240}},
+		},
+		{
+			name:  "weekend and holiday work does not use up the weekly threshold",
+			rules: OvertimeRules{WeeklyThreshold: 8 * time.Hour, WeekendMultiplier: 1.5, HolidayMultiplier: 2},
+			sessions: []model.Attendance{
+				holiday(session(day(0), 9, 480)), session(day(1), 9, 480),
+			},
+			expected: []minutes{{0, 480, 960}, {480, 0, 480}},
+		},
+		{
+			name:  "session spanning midnight counts towards the day it was checked in on",
+			rules: rules,
+			sessions: []model.Attendance{
+				session(day(0), 20, 360), session(day(1), 8, 480),
+			},
+			expected: []minutes{{360, 0, 360}, {480, 0, 480}},
+		},
+		{
+			name:     "friday night session ending on saturday is a weekday session",
+			rules:    rules,
+			sessions: []model.Attendance{session(day(4), 22, 540)},
+			expected: []minutes{{480, 60, 540}},
+		},
+		{
+			name:     "fractional weighted overtime is rounded",
+			rules:    rules,
+			sessions: []model.Attendance{session(day(5), 10, 45)},
+			expected: []minutes{{0, 45, 68}},
+		},
+		{
+			name:     "sessions without worked time",
+			rules:    rules,
+			sessions: []model.Attendance{{Date: day(0), RegularMinutes: 10, OvertimeMinutes: 10, WeightedMinutes: 10}},
+			expected: []minutes{{0, 0, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calculateHours(tt.rules, tt.sessions)
+			for i, expected := range tt.expected {
+				s := tt.sessions[i]
+				got := minutes{s.RegularMinutes, s.OvertimeMinutes, s.WeightedMinutes}
+				if got != expected {
+					t.Errorf("session %d: expected regular/overtime/weighted %v, got %v", i, expected, got)
+				}
+			}
+		})
+	}
+}
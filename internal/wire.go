@@ -5,11 +5,13 @@ package internal
 
 import (
 	"absence/internal/handler"
+	"absence/internal/job"
 	"absence/internal/middleware"
 	"absence/internal/repository"
 	"absence/internal/service"
 	"absence/pkg/database"
 	"absence/pkg/jwt"
+	"absence/pkg/notifier"
 
 	"github.com/google/wire"
 	"gorm.io/gorm"
@@ -21,22 +23,77 @@ func InitializeDB(config *database.Config) (*gorm.DB, error) {
 }
 
 // InitializeAPI initializes all components of the API
-func InitializeAPI(db *gorm.DB, jwtManager *jwt.JWTManager) (*API, error) {
+func InitializeAPI(db *gorm.DB, jwtManager *jwt.JWTManager, attendanceConfig *service.AttendanceConfig, passwordResetConfig *service.PasswordResetConfig, loginThrottleConfig *service.LoginThrottleConfig, twoFactorConfig *service.TwoFactorConfig, registrationConfig *service.RegistrationConfig, notifier notifier.Notifier) (*API, error) {
 	wire.Build(
 		repository.NewUserRepository,
 		repository.NewAttendanceRepository,
+		repository.NewAttendanceBreakRepository,
+		repository.NewLeaveRepository,
+		repository.NewDepartmentRepository,
+		repository.NewEmployeeDetailRepository,
+		repository.NewWorkScheduleRepository,
+		repository.NewHolidayRepository,
+		repository.NewGeofenceRepository,
+		repository.NewRoleRepository,
+		repository.NewTokenRepository,
+		repository.NewPasswordResetRepository,
+		repository.NewLoginAttemptRepository,
+		repository.NewTwoFactorRepository,
+		repository.NewInvitationRepository,
+		repository.NewAuditLogRepository,
+		repository.NewAttendanceCorrectionRepository,
 		service.NewUserService,
 		service.NewAttendanceService,
+		service.NewLeaveService,
+		service.NewDepartmentService,
+		service.NewEmployeeDetailService,
+		service.NewWorkScheduleService,
+		service.NewHolidayService,
+		service.NewGeofenceService,
+		service.NewRoleService,
+		service.NewTokenService,
+		service.NewLoginThrottleService,
+		service.NewInvitationService,
+		service.NewAuditLogService,
+		service.NewAttendanceCorrectionService,
 		handler.NewUserHandler,
 		handler.NewAttendanceHandler,
+		handler.NewLeaveHandler,
+		handler.NewDepartmentHandler,
+		handler.NewEmployeeDetailHandler,
+		handler.NewWorkScheduleHandler,
+		handler.NewHolidayHandler,
+		handler.NewGeofenceHandler,
+		handler.NewRoleHandler,
+		handler.NewJWKSHandler,
+		handler.NewLoginAttemptHandler,
+		handler.NewTwoFactorHandler,
+		handler.NewInvitationHandler,
+		handler.NewAuditLogHandler,
+		handler.NewAttendanceCorrectionHandler,
 		middleware.NewAuthMiddleware,
+		job.NewAbsenceJob,
 		wire.Struct(new(API), "*"),
 	)
 	return nil, nil
 }
 
 type API struct {
-	UserHandler       *handler.UserHandler
-	AttendanceHandler *handler.AttendanceHandler
-	AuthMiddleware    *middleware.AuthMiddleware
+	UserHandler                 *handler.UserHandler
+	AttendanceHandler           *handler.AttendanceHandler
+	LeaveHandler                *handler.LeaveHandler
+	DepartmentHandler           *handler.DepartmentHandler
+	EmployeeDetailHandler       *handler.EmployeeDetailHandler
+	WorkScheduleHandler         *handler.WorkScheduleHandler
+	HolidayHandler              *handler.HolidayHandler
+	GeofenceHandler             *handler.GeofenceHandler
+	RoleHandler                 *handler.RoleHandler
+	JWKSHandler                 *handler.JWKSHandler
+	LoginAttemptHandler         *handler.LoginAttemptHandler
+	TwoFactorHandler            *handler.TwoFactorHandler
+	InvitationHandler           *handler.InvitationHandler
+	AuditLogHandler             *handler.AuditLogHandler
+	AttendanceCorrectionHandler *handler.AttendanceCorrectionHandler
+	AuthMiddleware              *middleware.AuthMiddleware
+	AbsenceJob                  *job.AbsenceJob
 }
@@ -8,29 +8,86 @@ package internal
 
 import (
 	"absence/internal/handler"
+	"absence/internal/job"
 	"absence/internal/middleware"
 	"absence/internal/repository"
 	"absence/internal/service"
 	"absence/pkg/database"
 	"absence/pkg/jwt"
+	"absence/pkg/notifier"
 	"gorm.io/gorm"
 )
 
 // Injectors from wire.go:
 
 // InitializeAPI initializes all components of the API
-func InitializeAPI(db *gorm.DB, jwtManager *jwt.JWTManager) (*API, error) {
+func InitializeAPI(db *gorm.DB, jwtManager *jwt.JWTManager, attendanceConfig *service.AttendanceConfig, passwordResetConfig *service.PasswordResetConfig, loginThrottleConfig *service.LoginThrottleConfig, twoFactorConfig *service.TwoFactorConfig, registrationConfig *service.RegistrationConfig, notifier2 notifier.Notifier) (*API, error) {
 	userRepository := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepository)
-	userHandler := handler.NewUserHandler(userService, jwtManager)
+	passwordResetRepository := repository.NewPasswordResetRepository(db)
+	twoFactorRepository := repository.NewTwoFactorRepository(db)
+	userService := service.NewUserService(userRepository, passwordResetRepository, twoFactorRepository, notifier2, passwordResetConfig, twoFactorConfig, registrationConfig)
+	roleRepository := repository.NewRoleRepository(db)
+	tokenRepository := repository.NewTokenRepository(db)
+	tokenService := service.NewTokenService(tokenRepository, userRepository, roleRepository, jwtManager)
+	roleService := service.NewRoleService(roleRepository, userRepository, tokenService)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(db)
+	loginThrottleService := service.NewLoginThrottleService(loginAttemptRepository, loginThrottleConfig)
+	userHandler := handler.NewUserHandler(userService, roleService, tokenService, loginThrottleService)
 	attendanceRepository := repository.NewAttendanceRepository(db)
-	attendanceService := service.NewAttendanceService(attendanceRepository)
+	attendanceBreakRepository := repository.NewAttendanceBreakRepository(db)
+	workScheduleRepository := repository.NewWorkScheduleRepository(db)
+	departmentRepository := repository.NewDepartmentRepository(db)
+	workScheduleService := service.NewWorkScheduleService(workScheduleRepository, departmentRepository, userRepository)
+	holidayRepository := repository.NewHolidayRepository(db)
+	holidayService := service.NewHolidayService(holidayRepository)
+	leaveRepository := repository.NewLeaveRepository(db)
+	leaveService := service.NewLeaveService(leaveRepository, userRepository)
+	geofenceRepository := repository.NewGeofenceRepository(db)
+	geofenceService := service.NewGeofenceService(geofenceRepository, attendanceConfig)
+	attendanceService := service.NewAttendanceService(attendanceRepository, attendanceBreakRepository, userRepository, workScheduleService, holidayService, leaveService, geofenceService, attendanceConfig)
 	attendanceHandler := handler.NewAttendanceHandler(attendanceService)
-	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+	leaveHandler := handler.NewLeaveHandler(leaveService)
+	departmentService := service.NewDepartmentService(departmentRepository, userRepository)
+	departmentHandler := handler.NewDepartmentHandler(departmentService)
+	employeeDetailRepository := repository.NewEmployeeDetailRepository(db)
+	employeeDetailService := service.NewEmployeeDetailService(employeeDetailRepository, userRepository, departmentRepository)
+	employeeDetailHandler := handler.NewEmployeeDetailHandler(employeeDetailService)
+	workScheduleHandler := handler.NewWorkScheduleHandler(workScheduleService)
+	holidayHandler := handler.NewHolidayHandler(holidayService)
+	geofenceHandler := handler.NewGeofenceHandler(geofenceService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	jwksHandler := handler.NewJWKSHandler(jwtManager)
+	loginAttemptHandler := handler.NewLoginAttemptHandler(loginThrottleService)
+	twoFactorHandler := handler.NewTwoFactorHandler(userService, roleService)
+	invitationRepository := repository.NewInvitationRepository(db)
+	invitationService := service.NewInvitationService(invitationRepository, userRepository, roleRepository, departmentRepository, notifier2, registrationConfig)
+	invitationHandler := handler.NewInvitationHandler(invitationService)
+	auditLogRepository := repository.NewAuditLogRepository(db)
+	auditLogService := service.NewAuditLogService(auditLogRepository)
+	auditLogHandler := handler.NewAuditLogHandler(auditLogService)
+	attendanceCorrectionRepository := repository.NewAttendanceCorrectionRepository(db)
+	attendanceCorrectionService := service.NewAttendanceCorrectionService(attendanceCorrectionRepository, attendanceRepository, userRepository, attendanceService)
+	attendanceCorrectionHandler := handler.NewAttendanceCorrectionHandler(attendanceCorrectionService)
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager, tokenService)
+	absenceJob := job.NewAbsenceJob(attendanceService, attendanceConfig)
 	api := &API{
-		UserHandler:       userHandler,
-		AttendanceHandler: attendanceHandler,
-		AuthMiddleware:    authMiddleware,
+		UserHandler:                 userHandler,
+		AttendanceHandler:           attendanceHandler,
+		LeaveHandler:                leaveHandler,
+		DepartmentHandler:           departmentHandler,
+		EmployeeDetailHandler:       employeeDetailHandler,
+		WorkScheduleHandler:         workScheduleHandler,
+		HolidayHandler:              holidayHandler,
+		GeofenceHandler:             geofenceHandler,
+		RoleHandler:                 roleHandler,
+		JWKSHandler:                 jwksHandler,
+		LoginAttemptHandler:         loginAttemptHandler,
+		TwoFactorHandler:            twoFactorHandler,
+		InvitationHandler:           invitationHandler,
+		AuditLogHandler:             auditLogHandler,
+		AttendanceCorrectionHandler: attendanceCorrectionHandler,
+		AuthMiddleware:              authMiddleware,
+		AbsenceJob:                  absenceJob,
 	}
 	return api, nil
 }
@@ -43,7 +100,21 @@ func InitializeDB(config *database.Config) (*gorm.DB, error) {
 }
 
 type API struct {
-	UserHandler       *handler.UserHandler
-	AttendanceHandler *handler.AttendanceHandler
-	AuthMiddleware    *middleware.AuthMiddleware
+	UserHandler                 *handler.UserHandler
+	AttendanceHandler           *handler.AttendanceHandler
+	LeaveHandler                *handler.LeaveHandler
+	DepartmentHandler           *handler.DepartmentHandler
+	EmployeeDetailHandler       *handler.EmployeeDetailHandler
+	WorkScheduleHandler         *handler.WorkScheduleHandler
+	HolidayHandler              *handler.HolidayHandler
+	GeofenceHandler             *handler.GeofenceHandler
+	RoleHandler                 *handler.RoleHandler
+	JWKSHandler                 *handler.JWKSHandler
+	LoginAttemptHandler         *handler.LoginAttemptHandler
+	TwoFactorHandler            *handler.TwoFactorHandler
+	InvitationHandler           *handler.InvitationHandler
+	AuditLogHandler             *handler.AuditLogHandler
+	AttendanceCorrectionHandler *handler.AttendanceCorrectionHandler
+	AuthMiddleware              *middleware.AuthMiddleware
+	AbsenceJob                  *job.AbsenceJob
 }
@@ -0,0 +1,28 @@
+package audit
+
+import (
+	"context"
+)
+
+// Actor is who makes the changes recorded in the audit log; UserID is nil for unauthenticated requests and the system
+type Actor struct {
+	UserID   *uint
+	Username string
+	IP       string
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx whose database changes are attributed to the actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored by WithActor, or the zero Actor (the system) when there is none
+func ActorFromContext(ctx context.Context) Actor {
+	if ctx == nil {
+		return Actor{}
+	}
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
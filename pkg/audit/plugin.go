@@ -0,0 +1,275 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"absence/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ignoredTables only hold credentials and security bookkeeping, or are logs themselves; the changes
+// that matter, such as a new password or enabling two-factor authentication, show up on the users table
+var ignoredTables = map[string]bool{
+	"audit_logs":            true,
+	"login_attempts":        true,
+	"login_challenges":      true,
+	"password_reset_tokens": true,
+	"recovery_codes":        true,
+	"refresh_tokens":        true,
+	"revoked_tokens":        true,
+}
+
+const beforeKey = "audit:before"
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// Plugin writes an audit log entry for every row created, updated or deleted through gorm, in the same
+// transaction as the change. Columns tagged `audit:"-"` and automatic update timestamps are not
+// compared, and values of columns hidden from the API (`json:"-"`) are redacted.
+type Plugin struct{}
+
+func NewPlugin() *Plugin {
+	return &Plugin{}
+}
+
+func (p *Plugin) Name() string {
+	return "audit"
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	const commit = "gorm:commit_or_rollback_transaction"
+
+	if err := db.Callback().Create().After("gorm:create").Before(commit).Register("audit:after_create", afterCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("audit:before_update", loadBefore); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Before(commit).Register("audit:after_update", afterUpdate); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("audit:before_delete", loadBefore); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Before(commit).Register("audit:after_delete", afterDelete)
+}
+
+// audited reports whether the statement changes rows that belong in the audit log
+func audited(db *gorm.DB) bool {
+	stmt := db.Statement
+	return db.Error == nil &&
+		stmt.Schema != nil &&
+		stmt.Schema.PrioritizedPrimaryField != nil &&
+		!ignoredTables[stmt.Table]
+}
+
+func afterCreate(db *gorm.DB) {
+	if !audited(db) || db.Statement.RowsAffected == 0 {
+		return
+	}
+
+	var entries []model.AuditLog
+	value := reflect.Indirect(db.Statement.ReflectValue)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			entries = appendEntry(db, entries, model.AuditActionCreate, reflect.Value{}, reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		entries = appendEntry(db, entries, model.AuditActionCreate, reflect.Value{}, value)
+	}
+	write(db, entries)
+}
+
+// loadBefore remembers the rows an update or delete is about to change
+func loadBefore(db *gorm.DB) {
+	if !audited(db) {
+		return
+	}
+
+	stmt := db.Statement
+	var conditions []clause.Expression
+	if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok {
+		conditions = append(conditions, where.Exprs...)
+	}
+	// gorm adds the primary key of the model itself, e.g. for Save, while executing the statement
+	if value := reflect.Indirect(stmt.ReflectValue); value.Kind() == reflect.Struct {
+		for _, field := range stmt.Schema.PrimaryFields {
+			if key, zero := field.ValueOf(stmt.Context, value); !zero {
+				conditions = append(conditions, clause.Eq{Column: clause.Column{Name: field.DBName}, Value: key})
+			}
+		}
+	}
+	// gorm refuses updates and deletes without conditions
+	if len(conditions) == 0 {
+		return
+	}
+
+	rows := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+	query := db.Session(&gorm.Session{NewDB: true}).Clauses(clause.Where{Exprs: conditions})
+	if stmt.Unscoped {
+		query = query.Unscoped()
+	}
+	if err := query.Find(rows.Interface()).Error; err != nil {
+		db.AddError(fmt.Errorf("failed to load rows for the audit log: %w", err))
+		return
+	}
+	db.InstanceSet(beforeKey, rows.Elem())
+}
+
+func afterUpdate(db *gorm.DB) {
+	before, ok := loadedRows(db)
+	if !ok {
+		return
+	}
+
+	// Reload the same rows, including ones the update soft-deleted or restored, to see what changed
+	stmt := db.Statement
+	primaryKey := stmt.Schema.PrioritizedPrimaryField
+	keys := make([]interface{}, before.Len())
+	for i := range keys {
+		keys[i], _ = primaryKey.ValueOf(stmt.Context, before.Index(i))
+	}
+	rows := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Unscoped().
+		Where(clause.IN{Column: clause.Column{Name: primaryKey.DBName}, Values: keys}).
+		Find(rows.Interface()).Error
+	if err != nil {
+		db.AddError(fmt.Errorf("failed to load rows for the audit log: %w", err))
+		return
+	}
+
+	after := make(map[string]reflect.Value, rows.Elem().Len())
+	for i := 0; i < rows.Elem().Len(); i++ {
+		row := rows.Elem().Index(i)
+		after[entityID(db, row)] = row
+	}
+
+	var entries []model.AuditLog
+	for i := 0; i < before.Len(); i++ {
+		row := before.Index(i)
+		if updated, ok := after[entityID(db, row)]; ok {
+			entries = appendEntry(db, entries, model.AuditActionUpdate, row, updated)
+		}
+	}
+	write(db, entries)
+}
+
+func afterDelete(db *gorm.DB) {
+	before, ok := loadedRows(db)
+	if !ok || db.Statement.RowsAffected == 0 {
+		return
+	}
+
+	var entries []model.AuditLog
+	for i := 0; i < before.Len(); i++ {
+		entries = appendEntry(db, entries, model.AuditActionDelete, before.Index(i), reflect.Value{})
+	}
+	write(db, entries)
+}
+
+// loadedRows returns the rows remembered by loadBefore
+func loadedRows(db *gorm.DB) (reflect.Value, bool) {
+	if !audited(db) {
+		return reflect.Value{}, false
+	}
+	value, ok := db.InstanceGet(beforeKey)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	rows := value.(reflect.Value)
+	return rows, rows.Len() > 0
+}
+
+// appendEntry adds an entry for one row unless nothing worth recording changed; before is invalid
+// for creates and after is invalid for deletes
+func appendEntry(db *gorm.DB, entries []model.AuditLog, action string, before, after reflect.Value) []model.AuditLog {
+	changes := diff(db, before, after)
+	if len(changes) == 0 {
+		return entries
+	}
+
+	row := after
+	if !row.IsValid() {
+		row = before
+	}
+	return append(entries, model.AuditLog{
+		Action:   action,
+		Entity:   db.Statement.Table,
+		EntityID: entityID(db, row),
+		Changes:  changes,
+	})
+}
+
+func diff(db *gorm.DB, before, after reflect.Value) map[string]model.AuditChange {
+	ctx := db.Statement.Context
+	changes := make(map[string]model.AuditChange)
+	for _, field := range db.Statement.Schema.Fields {
+		if field.DBName == "" || field.AutoUpdateTime > 0 || field.Tag.Get("audit") == "-" {
+			continue
+		}
+
+		var change model.AuditChange
+		if before.IsValid() {
+			change.Old, _ = field.ValueOf(ctx, before)
+		}
+		if after.IsValid() {
+			change.New, _ = field.ValueOf(ctx, after)
+		}
+		if before.IsValid() && after.IsValid() && equal(change.Old, change.New) {
+			continue
+		}
+
+		if redacted(field) {
+			if before.IsValid() {
+				change.Old = model.AuditRedacted
+			}
+			if after.IsValid() {
+				change.New = model.AuditRedacted
+			}
+		}
+		changes[field.DBName] = change
+	}
+	return changes
+}
+
+// redacted reports whether the column is hidden from the API; soft delete timestamps are not sensitive
+func redacted(field *schema.Field) bool {
+	return field.Tag.Get("json") == "-" && field.FieldType != deletedAtType
+}
+
+// equal compares values the way they end up in the audit log, so that e.g. two pointers to the same time are equal
+func equal(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+func entityID(db *gorm.DB, row reflect.Value) string {
+	key, _ := db.Statement.Schema.PrioritizedPrimaryField.ValueOf(db.Statement.Context, row)
+	return fmt.Sprint(key)
+}
+
+// write stores the entries in the transaction of the change, so that a failed audit log rolls the change back
+func write(db *gorm.DB, entries []model.AuditLog) {
+	if len(entries) == 0 {
+		return
+	}
+
+	actor := ActorFromContext(db.Statement.Context)
+	for i := range entries {
+		entries[i].ActorID = actor.UserID
+		entries[i].ActorUsername = actor.Username
+		entries[i].IP = actor.IP
+	}
+	if err := db.Session(&gorm.Session{NewDB: true}).Create(&entries).Error; err != nil {
+		db.AddError(fmt.Errorf("failed to write the audit log: %w", err))
+	}
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"absence/internal/model"
+	"absence/pkg/audit"
 	"fmt"
 	"log"
 	"os"
@@ -42,6 +43,11 @@ func NewDatabase(config *Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %v", err)
 	}
 
+	// Record who created, updated or deleted what
+	if err := db.Use(audit.NewPlugin()); err != nil {
+		return nil, fmt.Errorf("failed to register audit plugin: %v", err)
+	}
+
 	return db, nil
 }
 
@@ -49,15 +55,84 @@ func NewDatabase(config *Config) (*gorm.DB, error) {
 func AutoMigrate(db *gorm.DB) error {
 	log.Println("Running database migrations...")
 
+	// Attendance.Location became LocationIn once check-out got its own location
+	if db.Migrator().HasColumn(&model.Attendance{}, "location") && !db.Migrator().HasColumn(&model.Attendance{}, "location_in") {
+		if err := db.Migrator().RenameColumn(&model.Attendance{}, "location", "location_in"); err != nil {
+			return fmt.Errorf("failed to rename attendance location column: %v", err)
+		}
+	}
+
+	// Attendance.Notes became NotesIn once check-out got its own notes
+	if db.Migrator().HasColumn(&model.Attendance{}, "notes") && !db.Migrator().HasColumn(&model.Attendance{}, "notes_in") {
+		if err := db.Migrator().RenameColumn(&model.Attendance{}, "notes", "notes_in"); err != nil {
+			return fmt.Errorf("failed to rename attendance notes column: %v", err)
+		}
+	}
+
+	// Roles moved from a fixed admin/employee CHECK constraint to the roles table
+	if db.Migrator().HasConstraint(&model.User{}, "chk_users_role") {
+		if err := db.Migrator().DropConstraint(&model.User{}, "chk_users_role"); err != nil {
+			return fmt.Errorf("failed to drop user role constraint: %v", err)
+		}
+	}
+
 	// Add all your models here
 	if err := db.AutoMigrate(
+		&model.Role{},
 		&model.User{},
 		&model.Department{},
+		&model.EmployeeDetail{},
+		&model.WorkSchedule{},
+		&model.Holiday{},
+		&model.Geofence{},
 		&model.Attendance{},
+		&model.AttendanceBreak{},
+		&model.LeaveType{},
+		&model.LeaveRequest{},
+		&model.RefreshToken{},
+		&model.RevokedToken{},
+		&model.PasswordResetToken{},
+		&model.LoginAttempt{},
+		&model.RecoveryCode{},
+		&model.LoginChallenge{},
+		&model.Invitation{},
+		&model.AuditLog{},
+		&model.AttendanceCorrection{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate database: %v", err)
 	}
 
+	// Backfill the workday, worked time and status of attendances recorded before those columns existed
+	if err := db.Model(&model.Attendance{}).
+		Where("date IS NULL AND check_in IS NOT NULL").
+		Update("date", gorm.Expr("DATE(check_in)")).Error; err != nil {
+		return fmt.Errorf("failed to backfill attendance dates: %v", err)
+	}
+	// Sessions without check-out stored the zero time before check_out became nullable
+	if err := db.Model(&model.Attendance{}).
+		Where("check_out < ?", "1000-01-01").
+		Update("check_out", nil).Error; err != nil {
+		return fmt.Errorf("failed to clear empty attendance check-outs: %v", err)
+	}
+	if err := db.Model(&model.Attendance{}).
+		Where("worked_minutes = 0 AND check_in IS NOT NULL AND check_out > check_in").
+		Update("worked_minutes", gorm.Expr("TIMESTAMPDIFF(MINUTE, check_in, check_out)")).Error; err != nil {
+		return fmt.Errorf("failed to backfill attendance worked minutes: %v", err)
+	}
+	if err := db.Model(&model.Attendance{}).
+		Where("status IS NULL OR status = ''").
+		Update("status", gorm.Expr("CASE WHEN check_in_status = ? THEN ? ELSE ? END",
+			model.ScheduleLate, model.AttendanceStatusLate, model.AttendanceStatusPresent)).Error; err != nil {
+		return fmt.Errorf("failed to backfill attendance statuses: %v", err)
+	}
+
+	// Seed the built-in roles without overwriting permissions edited since
+	for _, role := range model.DefaultRoles() {
+		if err := db.Where(model.Role{Name: role.Name}).FirstOrCreate(&role).Error; err != nil {
+			return fmt.Errorf("failed to seed role %s: %v", role.Name, err)
+		}
+	}
+
 	log.Println("Database migration completed successfully")
 	return nil
 }
@@ -1,6 +1,10 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -16,42 +20,93 @@ type Claims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// Permissions are the permissions granted by Role when the token was issued
+	Permissions []string `json:"permissions"`
+	// IssuedAtMilli is iat in milliseconds, precise enough to tell tokens apart from a revocation in the same second
+	IssuedAtMilli int64 `json:"iat_ms,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Issued returns when the token was issued, to the millisecond for tokens that carry iat_ms
+func (c *Claims) Issued() time.Time {
+	if c.IssuedAtMilli != 0 {
+		return time.UnixMilli(c.IssuedAtMilli)
+	}
+	if c.IssuedAt != nil {
+		return c.IssuedAt.Time
+	}
+	return time.Time{}
+}
+
 type JWTManager struct {
-	secretKey     []byte
-	tokenDuration time.Duration
+	keys            *KeySet
+	tokenDuration   time.Duration
+	refreshDuration time.Duration
 }
 
-func NewJWTManager(secretKey string, tokenDuration time.Duration) *JWTManager {
+func NewJWTManager(keys *KeySet, tokenDuration, refreshDuration time.Duration) *JWTManager {
 	return &JWTManager{
-		secretKey:     []byte(secretKey),
-		tokenDuration: tokenDuration,
+		keys:            keys,
+		tokenDuration:   tokenDuration,
+		refreshDuration: refreshDuration,
 	}
 }
 
-func (m *JWTManager) GenerateToken(userID uint, username, role string) (string, error) {
+// TokenDuration is how long issued access tokens stay valid
+func (m *JWTManager) TokenDuration() time.Duration {
+	return m.tokenDuration
+}
+
+func (m *JWTManager) GenerateToken(userID uint, username, role string, permissions []string) (string, error) {
+	// Every token gets a unique ID (jti) so it can be revoked on its own
+	tokenID, err := randomString(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
 	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
+		UserID:        userID,
+		Username:      username,
+		Role:          role,
+		Permissions:   permissions,
+		IssuedAtMilli: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.tokenDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.tokenDuration)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(m.secretKey)
+	key, err := m.keys.current(now)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signingKey())
+}
+
+// GenerateRefreshToken returns a new opaque refresh token and its expiry.
+// Only HashToken(token) should be stored; the token itself is handed to the client once.
+func (m *JWTManager) GenerateRefreshToken() (string, time.Time, error) {
+	token, err := NewOpaqueToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, time.Now().Add(m.refreshDuration), nil
 }
 
 func (m *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key := m.keys.lookup(kid, time.Now())
+		// The algorithm must be the key's own; never trust the alg header to pick the verification scheme
+		if key == nil || token.Method.Alg() != key.method().Alg() {
 			return nil, ErrInvalidToken
 		}
-		return m.secretKey, nil
+		return key.verificationKey(), nil
 	})
 
 	if err != nil {
@@ -68,3 +123,27 @@ func (m *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+// JWKS returns the public keys other services need to verify issued tokens
+func (m *JWTManager) JWKS() JWKS {
+	return m.keys.JWKS(time.Now())
+}
+
+// NewOpaqueToken returns a random URL-safe token for single-use secrets such as refresh or password reset tokens
+func NewOpaqueToken() (string, error) {
+	return randomString(32)
+}
+
+// HashToken returns the SHA-256 hex digest under which an opaque token is stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
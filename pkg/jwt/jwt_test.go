@@ -0,0 +1,52 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestManager(t *testing.T, keys ...*Key) *JWTManager {
+	t.Helper()
+	set, err := NewKeySet(keys...)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	return NewJWTManager(set, time.Hour, 24*time.Hour)
+}
+
+func TestGeneratedTokenIsIssuedToTheMillisecond(t *testing.T) {
+	manager := newTestManager(t, NewHMACKey("hmac", []byte("secret")))
+
+	before := time.Now().Truncate(time.Millisecond)
+	token, err := manager.GenerateToken(1, "alice", "employee", nil)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	after := time.Now()
+
+	claims, err := manager.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if claims.IssuedAtMilli == 0 {
+		t.Fatal("token has no iat_ms claim")
+	}
+	if issued := claims.Issued(); issued.Before(before) || issued.After(after) {
+		t.Errorf("Issued() = %v, want between %v and %v", issued, before, after)
+	}
+}
+
+func TestIssuedFallsBackToIssuedAt(t *testing.T) {
+	iat := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	claims := Claims{RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(iat)}}
+	if got := claims.Issued(); !got.Equal(iat) {
+		t.Errorf("Issued() = %v, want %v", got, iat)
+	}
+
+	claims.IssuedAtMilli = iat.Add(250 * time.Millisecond).UnixMilli()
+	if got, want := claims.Issued(), iat.Add(250*time.Millisecond); !got.Equal(want) {
+		t.Errorf("Issued() = %v, want %v", got, want)
+	}
+}
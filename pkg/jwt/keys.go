@@ -0,0 +1,278 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	ErrNoSigningKey         = errors.New("no signing key is active")
+	ErrDuplicateKeyID       = errors.New("duplicate key id")
+	ErrUnsupportedKey       = errors.New("unsupported key type")
+	ErrAlgorithmMismatch    = errors.New("key type does not match algorithm")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+)
+
+// Key is one signing key, identified in token headers by its ID (kid).
+// A key signs new tokens from ActiveFrom until a newer key becomes active,
+// and verifies tokens until RetireAt (if set).
+type Key struct {
+	ID         string
+	Algorithm  string
+	ActiveFrom time.Time
+	RetireAt   time.Time
+
+	secret  []byte
+	private crypto.Signer
+}
+
+// NewHMACKey returns an HS256 key using a shared secret; HMAC keys are never published in the JWKS
+func NewHMACKey(id string, secret []byte) *Key {
+	return &Key{
+		ID:        id,
+		Algorithm: AlgorithmHS256,
+		secret:    secret,
+	}
+}
+
+// ParsePrivateKeyPEM reads an RSA (PKCS#1 or PKCS#8) or Ed25519 (PKCS#8) private key.
+// The algorithm is inferred from the key type when empty.
+func ParsePrivateKeyPEM(id, algorithm string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM block found", id)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: %w: %s", id, ErrUnsupportedKey, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %v", id, err)
+	}
+
+	key := &Key{ID: id, Algorithm: algorithm}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		if key.Algorithm == "" {
+			key.Algorithm = AlgorithmRS256
+		}
+		if key.Algorithm != AlgorithmRS256 {
+			return nil, fmt.Errorf("key %s: %w", id, ErrAlgorithmMismatch)
+		}
+		key.private = private
+	case ed25519.PrivateKey:
+		if key.Algorithm == "" {
+			key.Algorithm = AlgorithmEdDSA
+		}
+		if key.Algorithm != AlgorithmEdDSA {
+			return nil, fmt.Errorf("key %s: %w", id, ErrAlgorithmMismatch)
+		}
+		key.private = private
+	default:
+		return nil, fmt.Errorf("key %s: %w", id, ErrUnsupportedKey)
+	}
+	return key, nil
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	switch k.Algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
+
+func (k *Key) signingKey() interface{} {
+	if k.private != nil {
+		return k.private
+	}
+	return k.secret
+}
+
+func (k *Key) verificationKey() interface{} {
+	if k.private != nil {
+		return k.private.Public()
+	}
+	return k.secret
+}
+
+func (k *Key) retired(now time.Time) bool {
+	return !k.RetireAt.IsZero() && !now.Before(k.RetireAt)
+}
+
+// KeySet holds every key that may sign or verify tokens
+type KeySet struct {
+	keys []*Key
+}
+
+// NewKeySet validates the keys and orders them by activation time
+func NewKeySet(keys ...*Key) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoSigningKey
+	}
+
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key.ID] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateKeyID, key.ID)
+		}
+		seen[key.ID] = true
+	}
+
+	sorted := append([]*Key(nil), keys...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ActiveFrom.Before(sorted[j].ActiveFrom)
+	})
+	return &KeySet{keys: sorted}, nil
+}
+
+// current returns the most recently activated key that is not retired
+func (s *KeySet) current(now time.Time) (*Key, error) {
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		key := s.keys[i]
+		if !key.ActiveFrom.After(now) && !key.retired(now) {
+			return key, nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// lookup finds a non-retired key by ID. Tokens without a kid predate key rotation and
+// can only have been signed by a single HMAC key.
+func (s *KeySet) lookup(id string, now time.Time) *Key {
+	for _, key := range s.keys {
+		if key.retired(now) {
+			continue
+		}
+		if key.ID == id || (id == "" && key.Algorithm == AlgorithmHS256) {
+			return key
+		}
+	}
+	return nil
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	// RSA public key
+	Modulus  string `json:"n,omitempty"`
+	Exponent string `json:"e,omitempty"`
+	// Ed25519 public key
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS publishes the public half of every asymmetric key that is not retired,
+// including keys scheduled for the future so verifiers can fetch them ahead of rotation
+func (s *KeySet) JWKS(now time.Time) JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range s.keys {
+		if key.private == nil || key.retired(now) {
+			continue
+		}
+
+		jwk := JWK{KeyID: key.ID, Algorithm: key.Algorithm, Use: "sig"}
+		switch public := key.private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.Modulus = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// keyManifest is the on-disk description of the signing keys and their rotation schedule
+type keyManifest struct {
+	Keys []struct {
+		ID             string    `json:"kid"`
+		Algorithm      string    `json:"algorithm"`
+		PrivateKeyFile string    `json:"private_key_file"`
+		ActiveFrom     time.Time `json:"active_from"`
+		RetireAt       time.Time `json:"retire_at"`
+	} `json:"keys"`
+}
+
+// LoadKeySet reads a JSON key manifest; private key paths are relative to the manifest's directory
+func LoadKeySet(manifestPath string) (*KeySet, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest keyManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid key manifest: %v", err)
+	}
+
+	keys := make([]*Key, 0, len(manifest.Keys))
+	for _, entry := range manifest.Keys {
+		if entry.Algorithm != "" && entry.Algorithm != AlgorithmRS256 && entry.Algorithm != AlgorithmEdDSA {
+			return nil, fmt.Errorf("key %s: %w: %s", entry.ID, ErrUnsupportedAlgorithm, entry.Algorithm)
+		}
+
+		path := entry.PrivateKeyFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(manifestPath), path)
+		}
+		pemData, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %v", entry.ID, err)
+		}
+
+		key, err := ParsePrivateKeyPEM(entry.ID, entry.Algorithm, pemData)
+		if err != nil {
+			return nil, err
+		}
+		key.ActiveFrom = entry.ActiveFrom
+		key.RetireAt = entry.RetireAt
+		keys = append(keys, key)
+	}
+	return NewKeySet(keys...)
+}
@@ -0,0 +1,183 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// newTestKey generates a private key and loads it the way key files are loaded
+func newTestKey(t *testing.T, id string, private crypto.Signer) *Key {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	key, err := ParsePrivateKeyPEM(id, "", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("ParsePrivateKeyPEM: %v", err)
+	}
+	return key
+}
+
+func newRSAKey(t *testing.T, id string) *Key {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return newTestKey(t, id, private)
+}
+
+func newEd25519Key(t *testing.T, id string) *Key {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return newTestKey(t, id, private)
+}
+
+// resign signs the claims of a token again with another method and key, keeping its kid
+func resign(t *testing.T, tokenString string, method jwt.SigningMethod, key interface{}) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified: %v", err)
+	}
+	token := jwt.NewWithClaims(method, parsed.Claims)
+	token.Header["kid"] = parsed.Header["kid"]
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func TestValidateTokenRejectsOtherAlgorithms(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa")
+	manager := newTestManager(t, rsaKey)
+
+	token, err := manager.GenerateToken(1, "alice", "employee", nil)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if _, err := manager.ValidateToken(token); err != nil {
+		t.Fatalf("ValidateToken of the original token: %v", err)
+	}
+
+	// The classic confusion attack: an HMAC signature keyed with the published public key
+	publicDER, err := x509.MarshalPKIXPublicKey(rsaKey.private.Public())
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "HS256 with the public key PEM", token: resign(t, token, jwt.SigningMethodHS256, publicPEM)},
+		{name: "HS256 with the public key DER", token: resign(t, token, jwt.SigningMethodHS256, publicDER)},
+		{name: "none", token: resign(t, token, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := manager.ValidateToken(tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("ValidateToken = %v, want %v", err, ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func TestValidateTokenRejectsUnknownKeyIDs(t *testing.T) {
+	manager := newTestManager(t, newEd25519Key(t, "current"))
+
+	tests := []struct {
+		name   string
+		signer *JWTManager
+	}{
+		{name: "unknown kid", signer: newTestManager(t, newEd25519Key(t, "other"))},
+		{name: "known kid of a different key", signer: newTestManager(t, newEd25519Key(t, "current"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.signer.GenerateToken(1, "alice", "employee", nil)
+			if err != nil {
+				t.Fatalf("GenerateToken: %v", err)
+			}
+			if _, err := manager.ValidateToken(token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("ValidateToken = %v, want %v", err, ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func TestValidateTokenWithoutKeyIDNeedsAnHMACKey(t *testing.T) {
+	secret := []byte("secret")
+	claims := Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	legacy := newTestManager(t, NewHMACKey("legacy", secret))
+	if _, err := legacy.ValidateToken(token); err != nil {
+		t.Errorf("ValidateToken with an HMAC key = %v, want nil", err)
+	}
+
+	rotated := newTestManager(t, newEd25519Key(t, "current"))
+	if _, err := rotated.ValidateToken(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("ValidateToken without an HMAC key = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestJWKSPublishesOnlyAsymmetricKeys(t *testing.T) {
+	secret := "a-shared-hmac-secret"
+	now := time.Now()
+
+	hmacKey := NewHMACKey("hmac", []byte(secret))
+	rsaKey := newRSAKey(t, "rsa")
+	rsaKey.ActiveFrom = now.Add(-time.Hour)
+	edKey := newEd25519Key(t, "ed")
+	edKey.ActiveFrom = now.Add(time.Hour)
+	retired := newEd25519Key(t, "retired")
+	retired.RetireAt = now.Add(-time.Minute)
+
+	set, err := NewKeySet(hmacKey, rsaKey, edKey, retired)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	jwks := set.JWKS(now)
+
+	ids := make([]string, 0, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		ids = append(ids, key.KeyID)
+		if key.KeyType != "RSA" && key.KeyType != "OKP" {
+			t.Errorf("key %s has type %q, want RSA or OKP", key.KeyID, key.KeyType)
+		}
+	}
+	if got, want := strings.Join(ids, ","), "rsa,ed"; got != want {
+		t.Errorf("JWKS key IDs = %s, want %s", got, want)
+	}
+
+	encoded, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	for _, leak := range []string{secret, `"oct"`, `"k":`, `"d":`} {
+		if strings.Contains(string(encoded), leak) {
+			t.Errorf("JWKS contains %s: %s", leak, encoded)
+		}
+	}
+}
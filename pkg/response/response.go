@@ -7,6 +7,30 @@ type Response struct {
 	Status  bool        `json:"status"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	// Meta describes the data as a whole, e.g. a *Pagination for one page of a list
+	Meta interface{} `json:"meta,omitempty"`
+}
+
+// Pagination describes which page of a list a response contains
+type Pagination struct {
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
+// NewPagination calculates the page count for a total number of items
+func NewPagination(page, pageSize int, total int64) *Pagination {
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	}
+	return &Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: totalPages,
+	}
 }
 
 // Success sends a success response
@@ -18,6 +42,21 @@ func Success(c *gin.Context, code int, message string, data interface{}) {
 	})
 }
 
+// SuccessWithMeta sends a success response with information about the data as a whole, such as totals
+func SuccessWithMeta(c *gin.Context, code int, message string, data interface{}, meta interface{}) {
+	c.JSON(code, Response{
+		Status:  true,
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
+// Paginated sends a success response for one page of a list
+func Paginated(c *gin.Context, code int, message string, data interface{}, meta *Pagination) {
+	SuccessWithMeta(c, code, message, data, meta)
+}
+
 // Error sends an error response
 func Error(c *gin.Context, code int, message string) {
 	c.JSON(code, Response{
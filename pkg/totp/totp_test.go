@@ -0,0 +1,74 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 test vectors, "12345678901234567890", in base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeMatchesRFC6238(t *testing.T) {
+	// The RFC lists eight digit codes; six digit codes are their last six digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := Code(rfc6238Secret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if code != tt.code {
+			t.Errorf("at %d: expected %s, got %s", tt.unix, tt.code, code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+	codeAt := func(step int64) string {
+		code, err := Code(rfc6238Secret, step)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		skew   int
+		step   int64
+		valid  bool
+	}{
+		{name: "current step", secret: rfc6238Secret, code: codeAt(current), skew: 1, step: current, valid: true},
+		{name: "previous step within skew", secret: rfc6238Secret, code: codeAt(current - 1), skew: 1, step: current - 1, valid: true},
+		{name: "next step within skew", secret: rfc6238Secret, code: codeAt(current + 1), skew: 1, step: current + 1, valid: true},
+		{name: "step outside skew", secret: rfc6238Secret, code: codeAt(current - 2), skew: 1},
+		{name: "no skew", secret: rfc6238Secret, code: codeAt(current - 1), skew: 0},
+		{name: "surrounding whitespace", secret: rfc6238Secret, code: " " + codeAt(current) + "\n", skew: 1, step: current, valid: true},
+		{name: "lowercase secret", secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", code: codeAt(current), skew: 1, step: current, valid: true},
+		{name: "wrong length", secret: rfc6238Secret, code: "1234567", skew: 1},
+		{name: "invalid secret", secret: "not base32!", code: codeAt(current), skew: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, valid := Validate(tt.secret, tt.code, now, tt.skew)
+			if valid != tt.valid || step != tt.step {
+				t.Errorf("expected (%d, %v), got (%d, %v)", tt.step, tt.valid, step, valid)
+			}
+		})
+	}
+}
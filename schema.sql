@@ -1,11 +1,9 @@
--- Create Users table
-CREATE TABLE users (
+-- Create Roles table
+CREATE TABLE roles (
     id SERIAL PRIMARY KEY,
-    username VARCHAR(50) NOT NULL UNIQUE,
-    password VARCHAR(255) NOT NULL,
-    full_name VARCHAR(100) NOT NULL,
-    email VARCHAR(100) NOT NULL UNIQUE,
-    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'employee')),
+    name VARCHAR(50) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    permissions TEXT NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -19,11 +17,116 @@ CREATE TABLE departments (
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Create Users table
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(50) NOT NULL UNIQUE,
+    password VARCHAR(255) NOT NULL,
+    full_name VARCHAR(100) NOT NULL,
+    email VARCHAR(100) NOT NULL UNIQUE,
+    role VARCHAR(50) NOT NULL REFERENCES roles(name),
+    department_id INTEGER REFERENCES departments(id),
+    totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    totp_secret VARCHAR(64),
+    totp_last_step BIGINT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+-- Create Refresh_Tokens table
+CREATE TABLE refresh_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Revoked_Tokens table (access token denylist; an empty jti revokes all of a user's tokens)
+CREATE TABLE revoked_tokens (
+    id SERIAL PRIMARY KEY,
+    jti VARCHAR(64),
+    user_id INTEGER NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6)
+);
+
+-- Create Password_Reset_Tokens table (single-use forgot-password tokens, stored hashed)
+CREATE TABLE password_reset_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Login_Attempts table (every login attempt; cleared failures no longer count towards a lockout)
+CREATE TABLE login_attempts (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(50) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(255),
+    outcome VARCHAR(20) NOT NULL CHECK (outcome IN ('success', 'failure', 'blocked')),
+    cleared_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Audit_Logs table (every created, updated or deleted row with who changed it and from where)
+CREATE TABLE audit_logs (
+    id SERIAL PRIMARY KEY,
+    actor_id INTEGER NULL,
+    actor_username VARCHAR(50),
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    entity VARCHAR(64) NOT NULL,
+    entity_id VARCHAR(64),
+    changes TEXT,
+    ip VARCHAR(45),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Recovery_Codes table (single-use two-factor backup codes, stored hashed)
+CREATE TABLE recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Login_Challenges table (pending second login steps, stored hashed)
+CREATE TABLE login_challenges (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Invitations table (single-use onboarding tokens, stored hashed)
+CREATE TABLE invitations (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(100) NOT NULL,
+    role VARCHAR(50) NOT NULL REFERENCES roles(name),
+    department_id INTEGER REFERENCES departments(id) ON DELETE SET NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by INTEGER NOT NULL REFERENCES users(id),
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Create Employee_Details table
 CREATE TABLE employee_details (
     id SERIAL PRIMARY KEY,
-    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
-    department_id INTEGER REFERENCES departments(id),
+    user_id INTEGER NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
     employee_id VARCHAR(20) NOT NULL UNIQUE,
     position VARCHAR(100) NOT NULL,
     join_date DATE NOT NULL,
@@ -31,18 +134,54 @@ CREATE TABLE employee_details (
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
--- Create Attendance table
+-- Create Geofences table
+CREATE TABLE geofences (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    radius_meters DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Attendance table (users are soft-deleted, so history is never removed with them).
+-- Each row with a check-in is one session; a workday (date) can have several.
 CREATE TABLE attendance (
     id SERIAL PRIMARY KEY,
-    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id),
+    date DATE NOT NULL,
     check_in TIMESTAMP,
     check_out TIMESTAMP,
     status VARCHAR(20) CHECK (status IN ('present', 'late', 'absent', 'leave')),
-    location_in POINT,
-    location_out POINT,
-    notes TEXT,
+    check_in_status VARCHAR(20),
+    check_out_status VARCHAR(20),
+    late_minutes INTEGER NOT NULL DEFAULT 0,
+    early_leave_minutes INTEGER NOT NULL DEFAULT 0,
+    holiday_overtime BOOLEAN NOT NULL DEFAULT FALSE,
+    break_minutes INTEGER NOT NULL DEFAULT 0,
+    worked_minutes INTEGER NOT NULL DEFAULT 0,
+    break_exceeded BOOLEAN NOT NULL DEFAULT FALSE,
+    break_missing BOOLEAN NOT NULL DEFAULT FALSE,
+    location_in TEXT,
+    latitude_in DOUBLE PRECISION,
+    longitude_in DOUBLE PRECISION,
+    geofence_in_id INTEGER REFERENCES geofences(id),
+    remote_check_in BOOLEAN NOT NULL DEFAULT FALSE,
+    notes_in TEXT,
+    device_in VARCHAR(100),
+    source_in VARCHAR(20),
+    location_out TEXT,
+    latitude_out DOUBLE PRECISION,
+    longitude_out DOUBLE PRECISION,
+    geofence_out_id INTEGER REFERENCES geofences(id),
+    remote_check_out BOOLEAN NOT NULL DEFAULT FALSE,
+    notes_out TEXT,
+    device_out VARCHAR(100),
+    source_out VARCHAR(20),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
 );
 
 -- Create Leave_Types table
@@ -57,13 +196,41 @@ CREATE TABLE leave_types (
 -- Create Leave_Requests table
 CREATE TABLE leave_requests (
     id SERIAL PRIMARY KEY,
-    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id),
     leave_type_id INTEGER REFERENCES leave_types(id),
     start_date DATE NOT NULL,
     end_date DATE NOT NULL,
     reason TEXT,
-    status VARCHAR(20) CHECK (status IN ('pending', 'approved', 'rejected')),
+    status VARCHAR(20) CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
     approved_by INTEGER REFERENCES users(id),
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Attendance_Breaks table (breaks taken during an attendance session)
+CREATE TABLE attendance_breaks (
+    id SERIAL PRIMARY KEY,
+    attendance_id INTEGER NOT NULL REFERENCES attendance(id),
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Attendance_Corrections table (proposed times; the replaced ones are kept once approved)
+CREATE TABLE attendance_corrections (
+    id SERIAL PRIMARY KEY,
+    attendance_id INTEGER NOT NULL REFERENCES attendance(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    check_in TIMESTAMP NULL,
+    check_out TIMESTAMP NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    decided_by INTEGER REFERENCES users(id),
+    decided_at TIMESTAMP NULL,
+    original_check_in TIMESTAMP NULL,
+    original_check_out TIMESTAMP NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -71,27 +238,59 @@ CREATE TABLE leave_requests (
 -- Create Work_Schedules table
 CREATE TABLE work_schedules (
     id SERIAL PRIMARY KEY,
-    department_id INTEGER REFERENCES departments(id),
-    day_of_week INTEGER CHECK (day_of_week BETWEEN 1 AND 7),
+    department_id INTEGER NOT NULL REFERENCES departments(id),
+    day_of_week INTEGER NOT NULL CHECK (day_of_week BETWEEN 1 AND 7),
     start_time TIME NOT NULL,
     end_time TIME NOT NULL,
+    grace_minutes INTEGER NOT NULL DEFAULT 0,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (department_id, day_of_week)
 );
 
 -- Create Holidays table
 CREATE TABLE holidays (
     id SERIAL PRIMARY KEY,
     name VARCHAR(100) NOT NULL,
-    date DATE NOT NULL,
+    date DATE NOT NULL UNIQUE,
     description TEXT,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
 -- Add indexes for better performance
+CREATE INDEX idx_users_department_id ON users(department_id);
+CREATE INDEX idx_users_role ON users(role);
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX idx_revoked_tokens_jti ON revoked_tokens(jti);
+CREATE INDEX idx_revoked_tokens_user_id ON revoked_tokens(user_id);
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+CREATE INDEX idx_login_attempts_username ON login_attempts(username);
+CREATE INDEX idx_login_attempts_ip ON login_attempts(ip);
+CREATE INDEX idx_login_attempts_created_at ON login_attempts(created_at);
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes(user_id);
+CREATE INDEX idx_login_challenges_user_id ON login_challenges(user_id);
+CREATE INDEX idx_invitations_email ON invitations(email);
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs(actor_id);
+CREATE INDEX idx_audit_logs_entity ON audit_logs(entity, entity_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+CREATE INDEX idx_users_deleted_at ON users(deleted_at);
+CREATE INDEX idx_attendance_deleted_at ON attendance(deleted_at);
 CREATE INDEX idx_attendance_user_id ON attendance(user_id);
-CREATE INDEX idx_attendance_date ON attendance((check_in::DATE));
+CREATE INDEX idx_attendance_user_date ON attendance(user_id, date);
+CREATE INDEX idx_attendance_breaks_attendance_id ON attendance_breaks(attendance_id);
 CREATE INDEX idx_leave_requests_user_id ON leave_requests(user_id);
 CREATE INDEX idx_leave_requests_status ON leave_requests(status);
-CREATE INDEX idx_employee_details_department ON employee_details(department_id); 
\ No newline at end of file
+CREATE INDEX idx_attendance_corrections_attendance_id ON attendance_corrections(attendance_id);
+CREATE INDEX idx_attendance_corrections_user_id ON attendance_corrections(user_id);
+CREATE INDEX idx_attendance_corrections_status ON attendance_corrections(status);
+CREATE INDEX idx_employee_details_user_id ON employee_details(user_id); 
+
+-- Seed built-in roles
+INSERT INTO roles (name, description, permissions) VALUES
+    ('admin', 'Full access to every feature', '["*"]'),
+    ('employee', 'Records their own attendance and leave', '[]'),
+    ('hr_manager', 'Manages employees, leave and the holiday calendar', '["users:read","users:manage","attendance:read","attendance:approve","leaves:read","leaves:approve","leave_types:manage","departments:manage","holidays:manage"]'),
+    ('supervisor', 'Reviews and decides on leave requests and attendance corrections of their own department', '["leaves:read:department","leaves:approve:department","attendance:approve:department"]'),
+    ('auditor', 'Read-only access to users, attendance, leave and the audit log', '["users:read","attendance:read","leaves:read","audit:read"]');